@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+)
+
+// ensureAdmin makes sure the account named by ADMIN_EMAIL exists and is an admin,
+// creating it with ADMIN_PASSWORD when missing. Without ADMIN_EMAIL nothing is done.
+func ensureAdmin(users repository.UserRepository) {
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("ADMIN_EMAIL")))
+	if email == "" {
+		return
+	}
+
+	user, err := users.GetByEmail(email)
+	if err == nil {
+		if user.Role != models.RoleAdmin {
+			user.Role = models.RoleAdmin
+			if err := users.Update(user); err != nil {
+				log.Fatalf("Failed to promote %s to admin: %v", email, err)
+			}
+			log.Printf("Promoted %s to admin", email)
+		}
+		return
+	}
+	if apperr.KindOf(err) != apperr.KindNotFound {
+		log.Fatalf("Failed to look up admin account: %v", err)
+	}
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if len(password) < 8 {
+		log.Fatal("ADMIN_PASSWORD must be at least 8 characters to create the admin account")
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Fatalf("Failed to hash admin password: %v", err)
+	}
+
+	admin := models.User{Email: email, Name: "Admin", PasswordHash: hash, Role: models.RoleAdmin}
+	if err := users.Create(&admin); err != nil {
+		log.Fatalf("Failed to create admin account: %v", err)
+	}
+	log.Printf("Created admin account %s", email)
+}
+
+// softDeleteRetention reads SOFT_DELETE_RETENTION_DAYS, how long deleted records
+// are kept before an admin purge removes them, 30 days by default
+func softDeleteRetention() time.Duration {
+	days := 30
+	if value := os.Getenv("SOFT_DELETE_RETENTION_DAYS"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days < 0 {
+			log.Fatalf("Invalid SOFT_DELETE_RETENTION_DAYS %q, expected a number of days", value)
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,149 @@
+// Package apperr defines the typed errors returned by repositories and handlers.
+// Each error carries a Kind that decides the HTTP status, a machine-readable code
+// and a message that is safe to show to clients. The underlying cause is kept for
+// logging but never rendered.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error and decides its HTTP status
+type Kind int
+
+// Error kinds
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnsupportedMediaType
+	KindPreconditionFailed
+	KindPreconditionRequired
+)
+
+// Status returns the HTTP status code for errors of this kind
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldError describes why a single request field failed validation.
+// Rule names the failed validation rule, such as required or max.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Dependent identifies a record that blocks a request, such as a book of an
+// author that cannot be deleted while it has books
+type Dependent struct {
+	Resource string
+	ID       uint
+	Name     string
+}
+
+// Error is a typed application error
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string
+	Fields     []FieldError
+	Dependents []Dependent
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports a missing resource
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict reports a request that clashes with the current state, such as a duplicate.
+// dependents optionally lists the records that cause the conflict.
+func Conflict(code, message string, dependents ...Dependent) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message, Dependents: dependents}
+}
+
+// UnsupportedMediaType reports a request body in a format the endpoint does not accept
+func UnsupportedMediaType(code, message string) *Error {
+	return &Error{Kind: KindUnsupportedMediaType, Code: code, Message: message}
+}
+
+// PreconditionFailed reports a conditional request, such as one with If-Match,
+// whose condition does not hold for the current state of the resource
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: message}
+}
+
+// PreconditionRequired reports a request that must be made conditional
+func PreconditionRequired(code, message string) *Error {
+	return &Error{Kind: KindPreconditionRequired, Code: code, Message: message}
+}
+
+// Validation reports invalid input. fields optionally lists the offending fields.
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// Unauthorized reports a missing or invalid credential
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden reports an authenticated caller that may not perform the request
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Internal wraps an unexpected error. Its message is generic so that driver
+// and database details never reach clients.
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "An unexpected error occurred", Err: err}
+}
+
+// From returns err as an *Error, wrapping untyped errors with Internal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+// KindOf returns the kind of err, KindInternal for untyped errors
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Token types, stored in the typ claim so a refresh token cannot be used as an access token
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// ErrInvalidToken is returned for malformed, expired or wrongly typed tokens
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims issued by TokenService
+type Claims struct {
+	TokenType string `json:"typ"`
+	Role      string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Identity is the authenticated caller, a user carried by a token or an API key.
+// Role changes reach the caller with the next refresh, which reloads the account.
+type Identity struct {
+	UserID   uint
+	Role     string
+	APIKeyID uint
+	Scopes   []string
+}
+
+// TokenPair is the result of a successful login or refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// TokenService issues and verifies HS256 signed access and refresh tokens
+type TokenService struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenService creates a TokenService signing with secret
+func NewTokenService(secret []byte, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{secret: secret, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// NewTokenServiceFromEnv configures a TokenService from JWT_SECRET, JWT_ACCESS_TTL_MINUTES
+// and JWT_REFRESH_TTL_HOURS. Without JWT_SECRET a random secret is generated, so tokens
+// do not survive a restart.
+func NewTokenServiceFromEnv() *TokenService {
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if len(secret) == 0 {
+		log.Println("Warning: JWT_SECRET is not set, using a random secret, tokens will be invalid after restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("Failed to generate JWT secret: %v", err)
+		}
+	}
+
+	accessTTL := time.Duration(envInt("JWT_ACCESS_TTL_MINUTES", 15)) * time.Minute
+	refreshTTL := time.Duration(envInt("JWT_REFRESH_TTL_HOURS", 24*7)) * time.Hour
+	return NewTokenService(secret, accessTTL, refreshTTL)
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// Issue creates a new access and refresh token for a user
+func (s *TokenService) Issue(identity Identity) (TokenPair, error) {
+	access, err := s.sign(identity, TokenTypeAccess, s.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refresh, err := s.sign(identity, TokenTypeRefresh, s.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: s.accessTTL}, nil
+}
+
+func (s *TokenService) sign(identity Identity, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		TokenType: tokenType,
+		Role:      identity.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(identity.UserID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// Parse verifies a token of the given type and returns the identity it was issued for
+func (s *TokenService) Parse(token, tokenType string) (Identity, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.TokenType != tokenType {
+		return Identity{}, fmt.Errorf("%w: expected a %s token", ErrInvalidToken, tokenType)
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	return Identity{UserID: uint(userID), Role: claims.Role}, nil
+}
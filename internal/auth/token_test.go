@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test secret")
+
+// signed signs claims for user 7 with a signing method and key
+func signed(t *testing.T, method jwt.SigningMethod, key any, tokenType string, expiresAt time.Time) string {
+	t.Helper()
+	claims := Claims{
+		TokenType: tokenType,
+		Role:      "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(7),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestTokenServiceIssueAndParse(t *testing.T) {
+	tokens := NewTokenService(testSecret, time.Minute, time.Hour)
+	pair, err := tokens.Issue(Identity{UserID: 7, Role: "librarian"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	identity, err := tokens.Parse(pair.AccessToken, TokenTypeAccess)
+	if err != nil || identity.UserID != 7 || identity.Role != "librarian" {
+		t.Errorf("Parse(access) = %+v, %v, want user 7, librarian", identity, err)
+	}
+	identity, err = tokens.Parse(pair.RefreshToken, TokenTypeRefresh)
+	if err != nil || identity.UserID != 7 {
+		t.Errorf("Parse(refresh) = %+v, %v, want user 7", identity, err)
+	}
+}
+
+func TestTokenServiceParseRejects(t *testing.T) {
+	tokens := NewTokenService(testSecret, time.Minute, time.Hour)
+	pair, err := tokens.Issue(Identity{UserID: 7, Role: "user"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	valid := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+	}{
+		{name: "refresh token as access token", token: pair.RefreshToken, tokenType: TokenTypeAccess},
+		{name: "access token as refresh token", token: pair.AccessToken, tokenType: TokenTypeRefresh},
+		{name: "expired", token: signed(t, jwt.SigningMethodHS256, testSecret, TokenTypeAccess, time.Now().Add(-time.Minute)), tokenType: TokenTypeAccess},
+		{name: "expired issued by the service", token: mustIssue(t, NewTokenService(testSecret, -time.Minute, time.Hour)), tokenType: TokenTypeAccess},
+		{name: "HS512", token: signed(t, jwt.SigningMethodHS512, testSecret, TokenTypeAccess, valid), tokenType: TokenTypeAccess},
+		{name: "none algorithm", token: signed(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, TokenTypeAccess, valid), tokenType: TokenTypeAccess},
+		{name: "other secret", token: signed(t, jwt.SigningMethodHS256, []byte("other secret"), TokenTypeAccess, valid), tokenType: TokenTypeAccess},
+		{name: "tampered", token: pair.AccessToken[:len(pair.AccessToken)-2] + "xx", tokenType: TokenTypeAccess},
+		{name: "garbage", token: "not a token", tokenType: TokenTypeAccess},
+		{name: "empty", token: "", tokenType: TokenTypeAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := tokens.Parse(tt.token, tt.tokenType)
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Parse() = %+v, %v, want ErrInvalidToken", identity, err)
+			}
+		})
+	}
+}
+
+// mustIssue returns the access token a service issues for user 7
+func mustIssue(t *testing.T, tokens *TokenService) string {
+	t.Helper()
+	pair, err := tokens.Issue(Identity{UserID: 7, Role: "user"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	return pair.AccessToken
+}
@@ -2,12 +2,13 @@ package database
 
 import (
 	"fmt"
-	"go-rest-api/internal/models"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -20,20 +21,47 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
-var DB *gorm.DB
+// openDialector returns the GORM dialector for the configured DB_DRIVER
+func openDialector(driver string) (gorm.Dialector, error) {
+	switch driver {
+	case "postgres":
+		host := getEnv("DB_HOST", "localhost")
+		user := getEnv("DB_USER", "postgres")
+		password := getEnv("DB_PASSWORD", "postgres")
+		dbname := getEnv("DB_NAME", "library")
+		port := getEnv("DB_PORT", "5432")
 
-func ConnectDatabase() {
+		dsn := fmt.Sprintf(
+			"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
+			host, user, password, dbname, port,
+		)
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		// DB_PATH is a file path or ":memory:"
+		return sqlite.Open(sqliteDSN(getEnv("DB_PATH", "library.db"))), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q, expected \"postgres\" or \"sqlite\"", driver)
+	}
+}
 
-	host := getEnv("DB_HOST", "localhost")
-	user := getEnv("DB_USER", "postgres")
-	password := getEnv("DB_PASSWORD", "postgres")
-	dbname := getEnv("DB_NAME", "library")
-	port := getEnv("DB_PORT", "5432")
+// sqliteDSN enables foreign keys on a SQLite path, which SQLite leaves off by
+// default, so that the REFERENCES constraints hold like they do on postgres
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_foreign_keys=1"
+}
 
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-		host, user, password, dbname, port,
-	)
+// ConnectDatabase opens the configured database and returns the handle.
+// The schema is managed by the migrations package, see `migrate up`.
+func ConnectDatabase() *gorm.DB {
+	driver := getEnv("DB_DRIVER", "postgres")
+	dialector, err := openDialector(driver)
+	if err != nil {
+		log.Fatalf("Invalid database configuration: %v", err)
+	}
 
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags),
@@ -46,13 +74,15 @@ func ConnectDatabase() {
 	)
 
 	maxRetries := 5
-	var err error
+	var db *gorm.DB
 
 	for i := 0; i < maxRetries; i++ {
 		log.Printf("Attempting to connect to the database (attempt %d/%d)...", i+1, maxRetries)
 
-		DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+		db, err = gorm.Open(dialector, &gorm.Config{
 			Logger: newLogger,
+			// Report unique violations as gorm.ErrDuplicatedKey on every driver
+			TranslateError: true,
 		})
 
 		if err != nil {
@@ -70,10 +100,14 @@ func ConnectDatabase() {
 		}
 	}
 
-	log.Println("Running database migrations...")
-	err = DB.AutoMigrate(&models.Author{}, &models.Book{}, &models.Review{})
-	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	if driver == "sqlite" {
+		// SQLite allows a single writer, and every connection to ":memory:" opens its own database
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("Failed to configure database: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
 	}
-	log.Println("Database migration completed successfully")
+
+	return db
 }
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestSQLiteForeignKeys(t *testing.T) {
+	for _, path := range []string{":memory:", "file::memory:?cache=private"} {
+		t.Setenv("DB_PATH", path)
+		dialector, err := openDialector("sqlite")
+		if err != nil {
+			t.Fatalf("openDialector() error = %v", err)
+		}
+		db, err := gorm.Open(dialector, &gorm.Config{})
+		if err != nil {
+			t.Fatalf("open %q: %v", path, err)
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatalf("open %q: %v", path, err)
+		}
+		defer sqlDB.Close()
+
+		var enabled int
+		if err := db.Raw("PRAGMA foreign_keys").Scan(&enabled).Error; err != nil {
+			t.Fatalf("read pragma: %v", err)
+		}
+		if enabled != 1 {
+			t.Errorf("foreign_keys with DB_PATH %q = %d, want 1", path, enabled)
+		}
+	}
+}
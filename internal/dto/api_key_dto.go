@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required" example:"Nightly import"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=books:read books:write authors:read authors:write reviews:read reviews:write" example:"books:read,books:write"`
+}
+
+// APIKeyResponse represents an API key without its secret
+type APIKeyResponse struct {
+	ID          uint       `json:"id" example:"1"`
+	Name        string     `json:"name" example:"Nightly import"`
+	Prefix      string     `json:"prefix" example:"lib_3f9a1c2e"`
+	Scopes      []string   `json:"scopes" example:"books:read,books:write"`
+	CreatedByID uint       `json:"created_by_id" example:"1"`
+	CreatedAt   time.Time  `json:"created_at" example:"2025-03-09T12:00:00Z"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" example:"2025-03-10T02:00:00Z"`
+}
+
+// APIKeySecretResponse is returned on create and rotate, the only time the key is shown
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"lib_3f9a1c2e5b7d..."`
+}
+
+// PaginatedAPIKeysResponse represents paginated API key list response
+type PaginatedAPIKeysResponse struct {
+	Data       []APIKeyResponse `json:"data"`
+	Total      int64            `json:"total" example:"100"`
+	Page       int              `json:"page" example:"1"`
+	PageSize   int              `json:"page_size" example:"10"`
+	TotalPages int              `json:"total_pages" example:"10"`
+}
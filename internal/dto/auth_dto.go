@@ -0,0 +1,50 @@
+package dto
+
+// RegisterRequest represents the request body for creating a user account
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"reader@example.com"`
+	Name     string `json:"name" binding:"required" example:"Samet"`
+	Password string `json:"password" binding:"required,min=8,max=72" example:"correct-horse-battery"`
+}
+
+// LoginRequest represents the request body for logging in
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"reader@example.com"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
+}
+
+// RefreshRequest represents the request body for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// UserResponse represents the response body for user information
+type UserResponse struct {
+	ID    uint   `json:"id" example:"1"`
+	Email string `json:"email" example:"reader@example.com"`
+	Name  string `json:"name" example:"Samet"`
+	Role  string `json:"role" example:"user" enums:"user,librarian,admin"`
+}
+
+// PaginatedUsersResponse represents paginated user list response
+type PaginatedUsersResponse struct {
+	Data       []UserResponse `json:"data"`
+	Total      int64          `json:"total" example:"100"`
+	Page       int            `json:"page" example:"1"`
+	PageSize   int            `json:"page_size" example:"10"`
+	TotalPages int            `json:"total_pages" example:"10"`
+}
+
+// UpdateUserRoleRequest represents the request body for changing a user's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user librarian admin" example:"librarian"`
+}
+
+// AuthResponse represents the tokens issued on register, login and refresh
+type AuthResponse struct {
+	User         UserResponse `json:"user"`
+	AccessToken  string       `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string       `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string       `json:"token_type" example:"Bearer"`
+	ExpiresIn    int          `json:"expires_in" example:"900"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // CreateAuthorRequest represents the request body for creating an author
 type CreateAuthorRequest struct {
 	Name      string `json:"name" binding:"required" example:"Oguz Atay"`
@@ -7,9 +9,10 @@ type CreateAuthorRequest struct {
 	BirthDate string `json:"birth_date" binding:"required" example:"1961-10-12"`
 }
 
-// UpdateAuthorRequest represents the request body for updating an author
+// UpdateAuthorRequest represents the full replacement of an author sent with PUT.
+// PATCH requests are applied to the current author in this form.
 type UpdateAuthorRequest struct {
-	Name      string `json:"name" example:"Oguz Atay"`
+	Name      string `json:"name" binding:"required" example:"Oguz Atay"`
 	Biography string `json:"biography" example:"Famous Turkish writer Oguz Atay"`
 	BirthDate string `json:"birth_date" example:"1961-10-12"`
 }
@@ -22,11 +25,32 @@ type AuthorResponse struct {
 	BirthDate string `json:"birth_date" example:"1961-10-12"`
 }
 
-// AuthorDetailResponse includes books in the response
+// AuthorDetailResponse includes books in the response. The author's own page
+// also lists every book the author is credited on, grouped by role.
 type AuthorDetailResponse struct {
-	ID        uint           `json:"id" example:"1"`
-	Name      string         `json:"name" example:"Oguz Atay"`
-	Biography string         `json:"biography" example:"The mixing of dream and reality in his works, metafiction being the main principle of fiction"`
-	BirthDate string         `json:"birth_date" example:"1961-10-12"`
-	Books     []BookResponse `json:"books,omitempty"`
+	ID          uint                      `json:"id" example:"1"`
+	Name        string                    `json:"name" example:"Oguz Atay"`
+	Biography   string                    `json:"biography" example:"The mixing of dream and reality in his works, metafiction being the main principle of fiction"`
+	BirthDate   string                    `json:"birth_date" example:"1961-10-12"`
+	BookCount   int64                     `json:"book_count" example:"3"`
+	Books       []BookResponse            `json:"books,omitempty"`
+	BooksByRole map[string][]BookResponse `json:"books_by_role,omitempty"`
+	DeletedAt   *time.Time                `json:"deleted_at,omitempty" example:"2025-03-10T02:00:00Z"`
+}
+
+// PaginatedAuthorsResponse represents paginated author list response
+type PaginatedAuthorsResponse struct {
+	Data       []AuthorDetailResponse `json:"data"`
+	Total      int64                  `json:"total" example:"100"`
+	Page       int                    `json:"page" example:"1"`
+	PageSize   int                    `json:"page_size" example:"10"`
+	TotalPages int                    `json:"total_pages" example:"10"`
+}
+
+// CursorAuthorsResponse represents a cursor paginated author list response
+type CursorAuthorsResponse struct {
+	Data       []AuthorDetailResponse `json:"data"`
+	NextCursor string                 `json:"next_cursor,omitempty" example:"eyJ2IjpbIjEwIl19"`
+	PrevCursor string                 `json:"prev_cursor,omitempty" example:"eyJ2IjpbIjEiXSwiYiI6dHJ1ZX0"`
+	PageSize   int                    `json:"page_size" example:"10"`
 }
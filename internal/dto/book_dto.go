@@ -1,43 +1,89 @@
 package dto
 
-// CreateBookRequest represents the request body for creating a book
+import "time"
+
+// ContributorRequest credits an author on a book in a role
+type ContributorRequest struct {
+	AuthorID uint   `json:"author_id" binding:"required" example:"1"`
+	Role     string `json:"role" binding:"required,oneof=author editor translator illustrator" example:"author"`
+}
+
+// CreateBookRequest represents the request body for creating a book. Books
+// with a single author send author_id, others list every contributor in
+// order, the first one in the author role becoming the primary author_id.
 type CreateBookRequest struct {
-	Title           string `json:"title" binding:"required" example:"Oguz Atay and The Unbearables"`
-	AuthorID        uint   `json:"author_id" binding:"required" example:"1"`
-	ISBN            string `json:"isbn" binding:"required" example:"9780747532699"`
-	PublicationYear int    `json:"publication_year" binding:"required" example:"1997"`
-	Description     string `json:"description" binding:"required" example:"Oguz Atay'ın first adventure"`
+	Title           string               `json:"title" binding:"required" example:"Oguz Atay and The Unbearables"`
+	AuthorID        uint                 `json:"author_id,omitempty" binding:"required_without=Contributors" example:"1"`
+	Contributors    []ContributorRequest `json:"contributors,omitempty" binding:"omitempty,max=50,dive"`
+	ISBN            string               `json:"isbn" binding:"required,isbn" example:"978-0-7475-3269-9"`
+	PublicationYear int                  `json:"publication_year" binding:"required" example:"1997"`
+	Description     string               `json:"description" binding:"required" example:"Oguz Atay'ın first adventure"`
+	Genres          []uint               `json:"genres,omitempty" binding:"omitempty,max=20,unique,dive,min=1" example:"1,3"`
+	Tags            []uint               `json:"tags,omitempty" binding:"omitempty,max=50,unique,dive,min=1" example:"2"`
+	Subjects        []uint               `json:"subjects,omitempty" binding:"omitempty,max=20,unique,dive,min=1" example:"14"`
+	SeriesID        *uint                `json:"series_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	SeriesPosition  *float64             `json:"series_position,omitempty" binding:"required_with=SeriesID,excluded_without=SeriesID,omitempty,gte=0" example:"2.5"`
 }
 
-// UpdateBookRequest represents the request body for updating a book
+// UpdateBookRequest represents the full replacement of a book sent with PUT.
+// PATCH requests are applied to the current book in this form, which has
+// contributors rather than author_id when the book has more than its author.
 type UpdateBookRequest struct {
-	Title           string `json:"title" example:"Oguz Atay and The Unbearables"`
-	AuthorID        uint   `json:"author_id" example:"1"`
-	ISBN            string `json:"isbn" example:"9780747532699"`
-	PublicationYear int    `json:"publication_year" example:"1997"`
-	Description     string `json:"description" example:"Oguz Atay'ın first adventure"`
+	Title           string               `json:"title" binding:"required" example:"Oguz Atay and The Unbearables"`
+	AuthorID        uint                 `json:"author_id,omitempty" binding:"required_without=Contributors" example:"1"`
+	Contributors    []ContributorRequest `json:"contributors,omitempty" binding:"omitempty,max=50,dive"`
+	ISBN            string               `json:"isbn" binding:"required,isbn" example:"978-0-7475-3269-9"`
+	PublicationYear int                  `json:"publication_year" example:"1997"`
+	Description     string               `json:"description" example:"Oguz Atay'ın first adventure"`
+	Genres          []uint               `json:"genres,omitempty" binding:"omitempty,max=20,unique,dive,min=1" example:"1,3"`
+	Tags            []uint               `json:"tags,omitempty" binding:"omitempty,max=50,unique,dive,min=1" example:"2"`
+	Subjects        []uint               `json:"subjects,omitempty" binding:"omitempty,max=20,unique,dive,min=1" example:"14"`
+	SeriesID        *uint                `json:"series_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	SeriesPosition  *float64             `json:"series_position,omitempty" binding:"required_with=SeriesID,excluded_without=SeriesID,omitempty,gte=0" example:"2.5"`
 }
 
 // BookResponse represents the response body for book information
 type BookResponse struct {
-	ID              uint   `json:"id" example:"1"`
-	Title           string `json:"title" example:"Oguz Atay and The Unbearables"`
-	AuthorID        uint   `json:"author_id" example:"1"`
-	ISBN            string `json:"isbn" example:"9780747532699"`
-	PublicationYear int    `json:"publication_year" example:"1997"`
-	Description     string `json:"description" example:"Oguz Atay'ın first adventure"`
+	ID              uint                  `json:"id" example:"1"`
+	Title           string                `json:"title" example:"Oguz Atay and The Unbearables"`
+	AuthorID        uint                  `json:"author_id" example:"1"`
+	ISBN            string                `json:"isbn" example:"978-0-7475-3269-9"`
+	ISBN13          string                `json:"isbn13,omitempty" example:"9780747532699"`
+	PublicationYear int                   `json:"publication_year" example:"1997"`
+	Description     string                `json:"description" example:"Oguz Atay'ın first adventure"`
+	SeriesID        *uint                 `json:"series_id,omitempty" example:"1"`
+	SeriesPosition  *float64              `json:"series_position,omitempty" example:"2.5"`
+	DeletedAt       *time.Time            `json:"deleted_at,omitempty" example:"2025-03-10T02:00:00Z"`
+	Availability    *AvailabilityResponse `json:"availability,omitempty"`
+}
+
+// ContributorResponse is an author credited on a book
+type ContributorResponse struct {
+	AuthorID uint   `json:"author_id" example:"1"`
+	Name     string `json:"name" example:"Oguz Atay"`
+	Role     string `json:"role" example:"author" enums:"author,editor,translator,illustrator"`
 }
 
-// BookDetailResponse includes author and reviews in the response
+// BookDetailResponse includes the primary author, every contributor in order,
+// reviews, the genres and tags of the book by name, its subjects by code, its
+// place in its series and the availability of its copies in the response
 type BookDetailResponse struct {
-	ID              uint             `json:"id" example:"1"`
-	Title           string           `json:"title" example:"Oguz Atay and The Unbearables"`
-	AuthorID        uint             `json:"author_id" example:"1"`
-	Author          AuthorResponse   `json:"author,omitempty"`
-	ISBN            string           `json:"isbn" example:"9780747532699"`
-	PublicationYear int              `json:"publication_year" example:"1997"`
-	Description     string           `json:"description" example:"Oguz Atay'ın first adventure"`
-	Reviews         []ReviewResponse `json:"reviews,omitempty"`
+	ID              uint                  `json:"id" example:"1"`
+	Title           string                `json:"title" example:"Oguz Atay and The Unbearables"`
+	AuthorID        uint                  `json:"author_id" example:"1"`
+	Author          AuthorResponse        `json:"author,omitempty"`
+	Contributors    []ContributorResponse `json:"contributors"`
+	ISBN            string                `json:"isbn" example:"978-0-7475-3269-9"`
+	ISBN13          string                `json:"isbn13,omitempty" example:"9780747532699"`
+	PublicationYear int                   `json:"publication_year" example:"1997"`
+	Description     string                `json:"description" example:"Oguz Atay'ın first adventure"`
+	Reviews         []ReviewResponse      `json:"reviews,omitempty"`
+	Editions        []EditionResponse     `json:"editions"`
+	Genres          []GenreResponse       `json:"genres"`
+	Tags            []TagResponse         `json:"tags"`
+	Subjects        []SubjectResponse     `json:"subjects"`
+	Series          *BookSeriesResponse   `json:"series,omitempty"`
+	Availability    *AvailabilityResponse `json:"availability,omitempty"`
 }
 
 // PaginatedBooksResponse represents paginated book list response
@@ -48,3 +94,38 @@ type PaginatedBooksResponse struct {
 	PageSize   int            `json:"page_size" example:"10"`
 	TotalPages int            `json:"total_pages" example:"10"`
 }
+
+// CursorBooksResponse represents a cursor paginated book list response
+type CursorBooksResponse struct {
+	Data       []BookResponse `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJ2IjpbIjEwIl19"`
+	PrevCursor string         `json:"prev_cursor,omitempty" example:"eyJ2IjpbIjEiXSwiYiI6dHJ1ZX0"`
+	PageSize   int            `json:"page_size" example:"10"`
+}
+
+// ISBN lookup statuses
+const (
+	ISBNLookupFound   = "found"
+	ISBNLookupUnknown = "unknown"
+	ISBNLookupInvalid = "invalid"
+)
+
+// ISBNLookupRequest represents a batch of scanned codes to resolve
+type ISBNLookupRequest struct {
+	ISBNs []string `json:"isbns" binding:"required,min=1,max=100" example:"9780747532699,0-7475-3269-9"`
+}
+
+// ISBNLookupResult is the outcome for one scanned code, in the order it was sent
+type ISBNLookupResult struct {
+	Query  string              `json:"query" example:"0-7475-3269-9"`
+	Status string              `json:"status" example:"found" enums:"found,unknown,invalid"`
+	ISBN13 string              `json:"isbn13,omitempty" example:"9780747532699"`
+	Book   *BookDetailResponse `json:"book,omitempty"`
+}
+
+// ISBNLookupResponse represents the result of a batch ISBN lookup
+type ISBNLookupResponse struct {
+	Results []ISBNLookupResult `json:"results"`
+	Unknown []string           `json:"unknown" example:"9780000000002"`
+	Invalid []string           `json:"invalid" example:"12345"`
+}
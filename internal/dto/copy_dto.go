@@ -0,0 +1,40 @@
+package dto
+
+// CreateCopyRequest represents the request body for adding a physical copy to a book
+type CreateCopyRequest struct {
+	Barcode         string `json:"barcode" binding:"required,max=100" example:"LIB-000123"`
+	ShelfLocation   string `json:"shelf_location,omitempty" binding:"max=100" example:"B2-14"`
+	Condition       string `json:"condition,omitempty" binding:"omitempty,oneof=new good fair poor" example:"good"`
+	AcquisitionDate string `json:"acquisition_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-09-01"`
+	Status          string `json:"status,omitempty" binding:"omitempty,oneof=available on_loan lost repair" example:"available"`
+}
+
+// UpdateCopyRequest represents the full replacement of a copy sent with PUT.
+// PATCH requests are applied to the current copy in this form.
+type UpdateCopyRequest struct {
+	Barcode         string `json:"barcode" binding:"required,max=100" example:"LIB-000123"`
+	ShelfLocation   string `json:"shelf_location,omitempty" binding:"max=100" example:"B2-14"`
+	Condition       string `json:"condition,omitempty" binding:"omitempty,oneof=new good fair poor" example:"good"`
+	AcquisitionDate string `json:"acquisition_date,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2024-09-01"`
+	Status          string `json:"status" binding:"required,oneof=available on_loan lost repair" example:"on_loan"`
+}
+
+// CopyResponse represents the response body for copy information
+type CopyResponse struct {
+	ID              uint   `json:"id" example:"1"`
+	BookID          uint   `json:"book_id" example:"1"`
+	Barcode         string `json:"barcode" example:"LIB-000123"`
+	ShelfLocation   string `json:"shelf_location,omitempty" example:"B2-14"`
+	Condition       string `json:"condition,omitempty" example:"good" enums:"new,good,fair,poor"`
+	AcquisitionDate string `json:"acquisition_date,omitempty" example:"2024-09-01"`
+	Status          string `json:"status" example:"available" enums:"available,on_loan,lost,repair"`
+}
+
+// AvailabilityResponse counts the copies of a book by status
+type AvailabilityResponse struct {
+	Total     int64 `json:"total" example:"4"`
+	Available int64 `json:"available" example:"2"`
+	OnLoan    int64 `json:"on_loan" example:"1"`
+	Lost      int64 `json:"lost" example:"0"`
+	Repair    int64 `json:"repair" example:"1"`
+}
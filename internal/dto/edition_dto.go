@@ -0,0 +1,32 @@
+package dto
+
+// CreateEditionRequest represents the request body for adding an edition to a book
+type CreateEditionRequest struct {
+	ISBN        string `json:"isbn" binding:"required,isbn" example:"978-0-7475-4215-5"`
+	Format      string `json:"format,omitempty" binding:"omitempty,oneof=hardcover paperback ebook audiobook" example:"paperback"`
+	Language    string `json:"language,omitempty" binding:"omitempty,bcp47_language_tag" example:"en-GB"`
+	PageCount   int    `json:"page_count,omitempty" binding:"omitempty,min=1,max=100000" example:"223"`
+	PublisherID *uint  `json:"publisher_id,omitempty" binding:"omitempty,min=1" example:"1"`
+}
+
+// UpdateEditionRequest represents the full replacement of an edition sent with PUT.
+// PATCH requests are applied to the current edition in this form.
+type UpdateEditionRequest struct {
+	ISBN        string `json:"isbn" binding:"required,isbn" example:"978-0-7475-4215-5"`
+	Format      string `json:"format,omitempty" binding:"omitempty,oneof=hardcover paperback ebook audiobook" example:"paperback"`
+	Language    string `json:"language,omitempty" binding:"omitempty,bcp47_language_tag" example:"en-GB"`
+	PageCount   int    `json:"page_count,omitempty" binding:"omitempty,min=1,max=100000" example:"223"`
+	PublisherID *uint  `json:"publisher_id,omitempty" binding:"omitempty,min=1" example:"1"`
+}
+
+// EditionResponse represents the response body for edition information
+type EditionResponse struct {
+	ID        uint               `json:"id" example:"2"`
+	BookID    uint               `json:"book_id" example:"1"`
+	ISBN      string             `json:"isbn" example:"978-0-7475-4215-5"`
+	ISBN13    string             `json:"isbn13,omitempty" example:"9780747542155"`
+	Format    string             `json:"format,omitempty" example:"paperback" enums:"hardcover,paperback,ebook,audiobook"`
+	Language  string             `json:"language,omitempty" example:"en-GB"`
+	PageCount int                `json:"page_count,omitempty" example:"223"`
+	Publisher *PublisherResponse `json:"publisher,omitempty"`
+}
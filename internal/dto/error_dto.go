@@ -0,0 +1,28 @@
+package dto
+
+// FieldErrorResponse describes a single invalid request field
+type FieldErrorResponse struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+// DependentResponse identifies a record that blocks the request
+type DependentResponse struct {
+	Resource string `json:"resource" example:"book"`
+	ID       uint   `json:"id" example:"7"`
+	Name     string `json:"name" example:"The Hobbit"`
+}
+
+// ProblemResponse is the RFC 7807 application/problem+json body returned for every error
+type ProblemResponse struct {
+	Type       string               `json:"type" example:"about:blank"`
+	Title      string               `json:"title" example:"Not Found"`
+	Status     int                  `json:"status" example:"404"`
+	Detail     string               `json:"detail" example:"Book not found"`
+	Instance   string               `json:"instance" example:"/api/v1/books/42"`
+	Code       string               `json:"code" example:"book_not_found"`
+	RequestID  string               `json:"request_id" example:"9f86d081884c7d65"`
+	Errors     []FieldErrorResponse `json:"errors,omitempty"`
+	Dependents []DependentResponse  `json:"dependents,omitempty"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// CreateGenreRequest represents the request body for creating a genre
+type CreateGenreRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"Science Fiction"`
+	Description string `json:"description" binding:"max=1000" example:"Speculative fiction about science and technology"`
+}
+
+// UpdateGenreRequest represents the full replacement of a genre sent with PUT.
+// PATCH requests are applied to the current genre in this form.
+type UpdateGenreRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"Science Fiction"`
+	Description string `json:"description" binding:"max=1000" example:"Speculative fiction about science and technology"`
+}
+
+// GenreResponse represents the response body for genre information
+type GenreResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	Name        string `json:"name" example:"Science Fiction"`
+	Description string `json:"description,omitempty" example:"Speculative fiction about science and technology"`
+}
+
+// GenreDetailResponse includes the number of books in the genre
+type GenreDetailResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	Name        string `json:"name" example:"Science Fiction"`
+	Description string `json:"description,omitempty" example:"Speculative fiction about science and technology"`
+	BookCount   int64  `json:"book_count" example:"12"`
+}
+
+// PaginatedGenresResponse represents paginated genre list response
+type PaginatedGenresResponse struct {
+	Data       []GenreDetailResponse `json:"data"`
+	Total      int64                 `json:"total" example:"100"`
+	Page       int                   `json:"page" example:"1"`
+	PageSize   int                   `json:"page_size" example:"10"`
+	TotalPages int                   `json:"total_pages" example:"10"`
+}
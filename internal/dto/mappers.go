@@ -1,8 +1,14 @@
 package dto
 
 import (
+	"fmt"
+	"go-rest-api/internal/isbn"
 	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Convert models to DTOs
@@ -20,16 +26,30 @@ func ToAuthorResponse(author models.Author) AuthorResponse {
 // ToAuthorDetailResponse converts an Author model to AuthorDetailResponse DTO
 func ToAuthorDetailResponse(author models.Author) AuthorDetailResponse {
 	bookResponses := make([]BookResponse, len(author.Books))
+	bookIDs := make(map[uint]bool)
 	for i, book := range author.Books {
 		bookResponses[i] = ToBookResponse(book)
+		bookIDs[book.ID] = true
+	}
+
+	var booksByRole map[string][]BookResponse
+	if len(author.Contributions) > 0 {
+		booksByRole = make(map[string][]BookResponse)
+		for _, contribution := range author.Contributions {
+			booksByRole[contribution.Role] = append(booksByRole[contribution.Role], ToBookResponse(contribution.Book))
+			bookIDs[contribution.BookID] = true
+		}
 	}
 
 	return AuthorDetailResponse{
-		ID:        author.ID,
-		Name:      author.Name,
-		Biography: author.Biography,
-		BirthDate: author.BirthDate,
-		Books:     bookResponses,
+		ID:          author.ID,
+		Name:        author.Name,
+		Biography:   author.Biography,
+		BirthDate:   author.BirthDate,
+		BookCount:   int64(len(bookIDs)),
+		Books:       bookResponses,
+		BooksByRole: booksByRole,
+		DeletedAt:   deletedAt(author.DeletedAt),
 	}
 }
 
@@ -40,27 +60,110 @@ func ToBookResponse(book models.Book) BookResponse {
 		Title:           book.Title,
 		AuthorID:        book.AuthorID,
 		ISBN:            book.ISBN,
+		ISBN13:          derefString(book.ISBN13),
 		PublicationYear: book.PublicationYear,
 		Description:     book.Description,
+		SeriesID:        book.SeriesID,
+		SeriesPosition:  book.SeriesPosition,
+		DeletedAt:       deletedAt(book.DeletedAt),
+		Availability:    toAvailabilityResponse(book.Availability),
 	}
 }
 
 // ToBookDetailResponse converts a Book model to BookDetailResponse DTO
 func ToBookDetailResponse(book models.Book) BookDetailResponse {
+	contributorResponses := make([]ContributorResponse, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		contributorResponses[i] = ContributorResponse{
+			AuthorID: contributor.AuthorID,
+			Name:     contributor.Author.Name,
+			Role:     contributor.Role,
+		}
+	}
+
 	reviewResponses := make([]ReviewResponse, len(book.Reviews))
 	for i, review := range book.Reviews {
 		reviewResponses[i] = ToReviewResponse(review)
 	}
 
+	editionResponses := make([]EditionResponse, len(book.Editions))
+	for i, edition := range book.Editions {
+		editionResponses[i] = ToEditionResponse(edition)
+	}
+
+	genreResponses := make([]GenreResponse, len(book.Genres))
+	for i, genre := range book.Genres {
+		genreResponses[i] = ToGenreResponse(genre)
+	}
+
+	tagResponses := make([]TagResponse, len(book.Tags))
+	for i, tag := range book.Tags {
+		tagResponses[i] = ToTagResponse(tag)
+	}
+
+	subjectResponses := make([]SubjectResponse, len(book.Subjects))
+	for i, subject := range book.Subjects {
+		subjectResponses[i] = ToSubjectResponse(subject)
+	}
+
 	return BookDetailResponse{
 		ID:              book.ID,
 		Title:           book.Title,
 		AuthorID:        book.AuthorID,
 		Author:          ToAuthorResponse(book.Author),
+		Contributors:    contributorResponses,
 		ISBN:            book.ISBN,
+		ISBN13:          derefString(book.ISBN13),
 		PublicationYear: book.PublicationYear,
 		Description:     book.Description,
 		Reviews:         reviewResponses,
+		Editions:        editionResponses,
+		Genres:          genreResponses,
+		Tags:            tagResponses,
+		Subjects:        subjectResponses,
+		Series:          toBookSeriesResponse(book),
+		Availability:    toAvailabilityResponse(book.Availability),
+	}
+}
+
+// toAvailabilityResponse converts the copy counts of a book, nil when they were not loaded
+func toAvailabilityResponse(availability *models.Availability) *AvailabilityResponse {
+	if availability == nil {
+		return nil
+	}
+	return &AvailabilityResponse{
+		Total:     availability.Total,
+		Available: availability.Available,
+		OnLoan:    availability.OnLoan,
+		Lost:      availability.Lost,
+		Repair:    availability.Repair,
+	}
+}
+
+// toBookSeriesResponse places a book with its loaded series and neighbours in
+// the series, nil when it is not in one
+func toBookSeriesResponse(book models.Book) *BookSeriesResponse {
+	if book.Series == nil || book.SeriesPosition == nil {
+		return nil
+	}
+	return &BookSeriesResponse{
+		ID:       book.Series.ID,
+		Name:     book.Series.Name,
+		Position: *book.SeriesPosition,
+		Previous: toSeriesEntryResponse(book.PreviousInSeries),
+		Next:     toSeriesEntryResponse(book.NextInSeries),
+	}
+}
+
+func toSeriesEntryResponse(book *models.Book) *SeriesEntryResponse {
+	if book == nil || book.SeriesPosition == nil {
+		return nil
+	}
+	return &SeriesEntryResponse{
+		ID:       book.ID,
+		Title:    book.Title,
+		Position: *book.SeriesPosition,
+		Link:     fmt.Sprintf("/api/v1/books/%d", book.ID),
 	}
 }
 
@@ -72,9 +175,227 @@ func ToReviewResponse(review models.Review) ReviewResponse {
 		Comment:    review.Comment,
 		DatePosted: review.DatePosted,
 		BookID:     review.BookID,
+		UserID:     review.UserID,
+		DeletedAt:  deletedAt(review.DeletedAt),
+	}
+}
+
+// ToEditionResponse converts an Edition model, with its publisher loaded, to EditionResponse DTO
+func ToEditionResponse(edition models.Edition) EditionResponse {
+	response := EditionResponse{
+		ID:        edition.ID,
+		BookID:    edition.BookID,
+		ISBN:      edition.ISBN,
+		ISBN13:    derefString(edition.ISBN13),
+		Format:    edition.Format,
+		Language:  edition.Language,
+		PageCount: edition.PageCount,
+	}
+	if edition.Publisher != nil {
+		publisher := ToPublisherResponse(*edition.Publisher)
+		response.Publisher = &publisher
+	}
+	return response
+}
+
+// ToCopyResponse converts a Copy model to CopyResponse DTO
+func ToCopyResponse(bookCopy models.Copy) CopyResponse {
+	return CopyResponse{
+		ID:              bookCopy.ID,
+		BookID:          bookCopy.BookID,
+		Barcode:         bookCopy.Barcode,
+		ShelfLocation:   bookCopy.ShelfLocation,
+		Condition:       bookCopy.Condition,
+		AcquisitionDate: bookCopy.AcquisitionDate,
+		Status:          bookCopy.Status,
 	}
 }
 
+// ToPublisherResponse converts a Publisher model to PublisherResponse DTO
+func ToPublisherResponse(publisher models.Publisher) PublisherResponse {
+	return PublisherResponse{
+		ID:   publisher.ID,
+		Name: publisher.Name,
+	}
+}
+
+// ToPublisherDetailResponse converts a Publisher model with its number of editions to PublisherDetailResponse DTO
+func ToPublisherDetailResponse(publisher models.Publisher, editionCount int64) PublisherDetailResponse {
+	return PublisherDetailResponse{
+		ID:           publisher.ID,
+		Name:         publisher.Name,
+		EditionCount: editionCount,
+	}
+}
+
+// ToGenreResponse converts a Genre model to GenreResponse DTO
+func ToGenreResponse(genre models.Genre) GenreResponse {
+	return GenreResponse{
+		ID:          genre.ID,
+		Name:        genre.Name,
+		Description: genre.Description,
+	}
+}
+
+// ToGenreDetailResponse converts a Genre model with its number of books to GenreDetailResponse DTO
+func ToGenreDetailResponse(genre models.Genre, bookCount int64) GenreDetailResponse {
+	return GenreDetailResponse{
+		ID:          genre.ID,
+		Name:        genre.Name,
+		Description: genre.Description,
+		BookCount:   bookCount,
+	}
+}
+
+// ToTagResponse converts a Tag model to TagResponse DTO
+func ToTagResponse(tag models.Tag) TagResponse {
+	return TagResponse{
+		ID:   tag.ID,
+		Name: tag.Name,
+	}
+}
+
+// ToTagDetailResponse converts a Tag model with its number of books to TagDetailResponse DTO
+func ToTagDetailResponse(tag models.Tag, bookCount int64) TagDetailResponse {
+	return TagDetailResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		BookCount: bookCount,
+	}
+}
+
+// ToSubjectResponse converts a Subject model to SubjectResponse DTO
+func ToSubjectResponse(subject models.Subject) SubjectResponse {
+	return SubjectResponse{
+		ID:       subject.ID,
+		Code:     subject.Code,
+		Label:    subject.Label,
+		ParentID: subject.ParentID,
+	}
+}
+
+// ToSubjectDetailResponse converts a Subject model with its ancestors to SubjectDetailResponse DTO
+func ToSubjectDetailResponse(subject models.Subject, ancestors []models.Subject) SubjectDetailResponse {
+	ancestorResponses := make([]SubjectResponse, len(ancestors))
+	for i, ancestor := range ancestors {
+		ancestorResponses[i] = ToSubjectResponse(ancestor)
+	}
+
+	return SubjectDetailResponse{
+		ID:        subject.ID,
+		Code:      subject.Code,
+		Label:     subject.Label,
+		ParentID:  subject.ParentID,
+		Ancestors: ancestorResponses,
+	}
+}
+
+// ToSubjectTreeResponse nests a subtree, the root followed by its descendants
+// in path order as returned by SubjectRepository.GetSubtree, under its root
+func ToSubjectTreeResponse(subtree []models.Subject) SubjectTreeResponse {
+	// Sorting by path puts every subject right after its parent and before the
+	// next sibling of its parent, so each child is read by a recursive call
+	var build func(i int) (SubjectTreeResponse, int)
+	build = func(i int) (SubjectTreeResponse, int) {
+		node := SubjectTreeResponse{
+			ID:       subtree[i].ID,
+			Code:     subtree[i].Code,
+			Label:    subtree[i].Label,
+			Children: []SubjectTreeResponse{},
+		}
+		next := i + 1
+		for next < len(subtree) && subtree[next].ParentID != nil && *subtree[next].ParentID == subtree[i].ID {
+			var child SubjectTreeResponse
+			child, next = build(next)
+			node.Children = append(node.Children, child)
+		}
+		return node, next
+	}
+
+	if len(subtree) == 0 {
+		return SubjectTreeResponse{Children: []SubjectTreeResponse{}}
+	}
+	root, _ := build(0)
+	return root
+}
+
+// ToSeriesResponse converts a Series model to SeriesResponse DTO
+func ToSeriesResponse(series models.Series) SeriesResponse {
+	return SeriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+	}
+}
+
+// ToSeriesSummaryResponse converts a Series model with its number of books to SeriesSummaryResponse DTO
+func ToSeriesSummaryResponse(series models.Series, bookCount int64) SeriesSummaryResponse {
+	return SeriesSummaryResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		BookCount:   bookCount,
+	}
+}
+
+// ToSeriesDetailResponse converts a Series model with its books in reading order to SeriesDetailResponse DTO
+func ToSeriesDetailResponse(series models.Series, books []models.Book) SeriesDetailResponse {
+	bookResponses := make([]BookResponse, len(books))
+	for i, book := range books {
+		bookResponses[i] = ToBookResponse(book)
+	}
+
+	return SeriesDetailResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		Books:       bookResponses,
+	}
+}
+
+// ToUserResponse converts a User model to UserResponse DTO
+func ToUserResponse(user models.User) UserResponse {
+	return UserResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		Name:  user.Name,
+		Role:  user.Role,
+	}
+}
+
+// ToAPIKeyResponse converts an APIKey model to APIKeyResponse DTO
+func ToAPIKeyResponse(key models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      strings.Fields(key.Scopes),
+		CreatedByID: key.CreatedByID,
+		CreatedAt:   key.CreatedAt,
+		LastUsedAt:  key.LastUsedAt,
+	}
+}
+
+// ToSearchResultResponse converts a repository SearchHit to SearchResultResponse DTO
+func ToSearchResultResponse(hit repository.SearchHit) SearchResultResponse {
+	response := SearchResultResponse{
+		Type:       hit.Type,
+		Score:      hit.Rank,
+		Highlights: hit.Highlights,
+	}
+	if hit.Book != nil {
+		book := ToBookResponse(*hit.Book)
+		response.ID = book.ID
+		response.Book = &book
+	}
+	if hit.Author != nil {
+		author := ToAuthorResponse(*hit.Author)
+		response.ID = author.ID
+		response.Author = &author
+	}
+	return response
+}
+
 // Convert DTOs to models
 
 // CreateAuthorRequestToModel converts CreateAuthorRequest DTO to Author model
@@ -86,65 +407,352 @@ func CreateAuthorRequestToModel(req CreateAuthorRequest) models.Author {
 	}
 }
 
-// UpdateAuthorModelFromRequest updates Author model from UpdateAuthorRequest DTO
-func UpdateAuthorModelFromRequest(author *models.Author, req UpdateAuthorRequest) {
-	if req.Name != "" {
-		author.Name = req.Name
-	}
-	if req.Biography != "" {
-		author.Biography = req.Biography
-	}
-	if req.BirthDate != "" {
-		author.BirthDate = req.BirthDate
+// ToUpdateAuthorRequest converts an Author model to the UpdateAuthorRequest DTO that would leave it unchanged
+func ToUpdateAuthorRequest(author models.Author) UpdateAuthorRequest {
+	return UpdateAuthorRequest{
+		Name:      author.Name,
+		Biography: author.Biography,
+		BirthDate: author.BirthDate,
 	}
 }
 
+// UpdateAuthorModelFromRequest replaces the fields of Author model with UpdateAuthorRequest DTO
+func UpdateAuthorModelFromRequest(author *models.Author, req UpdateAuthorRequest) {
+	author.Name = req.Name
+	author.Biography = req.Biography
+	author.BirthDate = req.BirthDate
+}
+
 // CreateBookRequestToModel converts CreateBookRequest DTO to Book model
 func CreateBookRequestToModel(req CreateBookRequest) models.Book {
+	contributors, authorID := contributorsFromRequest(req.AuthorID, req.Contributors)
 	return models.Book{
 		Title:           req.Title,
-		AuthorID:        req.AuthorID,
-		ISBN:            req.ISBN,
+		AuthorID:        authorID,
+		Contributors:    contributors,
+		ISBN:            strings.TrimSpace(req.ISBN),
+		ISBN13:          normalizeISBN(req.ISBN),
 		PublicationYear: req.PublicationYear,
 		Description:     req.Description,
+		Genres:          genresFromIDs(req.Genres),
+		Tags:            tagsFromIDs(req.Tags),
+		Subjects:        subjectsFromIDs(req.Subjects),
+		SeriesID:        req.SeriesID,
+		SeriesPosition:  req.SeriesPosition,
 	}
 }
 
-// UpdateBookModelFromRequest updates Book model from UpdateBookRequest DTO
+// ToUpdateBookRequest converts a Book model to the UpdateBookRequest DTO that would leave it unchanged.
+// Books credited to their author alone have author_id, others their contributors.
+func ToUpdateBookRequest(book models.Book) UpdateBookRequest {
+	req := UpdateBookRequest{
+		Title:           book.Title,
+		ISBN:            book.ISBN,
+		PublicationYear: book.PublicationYear,
+		Description:     book.Description,
+		SeriesID:        book.SeriesID,
+		SeriesPosition:  book.SeriesPosition,
+	}
+	for _, genre := range book.Genres {
+		req.Genres = append(req.Genres, genre.ID)
+	}
+	for _, tag := range book.Tags {
+		req.Tags = append(req.Tags, tag.ID)
+	}
+	for _, subject := range book.Subjects {
+		req.Subjects = append(req.Subjects, subject.ID)
+	}
+	if len(book.Contributors) > 1 || (len(book.Contributors) == 1 && book.Contributors[0].Role != models.ContributorAuthor) {
+		req.Contributors = make([]ContributorRequest, len(book.Contributors))
+		for i, contributor := range book.Contributors {
+			req.Contributors[i] = ContributorRequest{AuthorID: contributor.AuthorID, Role: contributor.Role}
+		}
+	} else {
+		req.AuthorID = book.AuthorID
+	}
+	return req
+}
+
+// UpdateBookModelFromRequest replaces the fields of Book model with UpdateBookRequest DTO
 func UpdateBookModelFromRequest(book *models.Book, req UpdateBookRequest) {
-	if req.Title != "" {
-		book.Title = req.Title
+	book.Title = req.Title
+	book.Contributors, book.AuthorID = contributorsFromRequest(req.AuthorID, req.Contributors)
+	book.ISBN = strings.TrimSpace(req.ISBN)
+	book.ISBN13 = normalizeISBN(req.ISBN)
+	book.PublicationYear = req.PublicationYear
+	book.Description = req.Description
+	book.Genres = genresFromIDs(req.Genres)
+	book.Tags = tagsFromIDs(req.Tags)
+	book.Subjects = subjectsFromIDs(req.Subjects)
+	book.SeriesID = req.SeriesID
+	book.SeriesPosition = req.SeriesPosition
+}
+
+// CreateGenreRequestToModel converts CreateGenreRequest DTO to Genre model
+func CreateGenreRequestToModel(req CreateGenreRequest) models.Genre {
+	return models.Genre{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+}
+
+// ToUpdateGenreRequest converts a Genre model to the UpdateGenreRequest DTO that would leave it unchanged
+func ToUpdateGenreRequest(genre models.Genre) UpdateGenreRequest {
+	return UpdateGenreRequest{
+		Name:        genre.Name,
+		Description: genre.Description,
+	}
+}
+
+// UpdateGenreModelFromRequest replaces the fields of Genre model with UpdateGenreRequest DTO
+func UpdateGenreModelFromRequest(genre *models.Genre, req UpdateGenreRequest) {
+	genre.Name = req.Name
+	genre.Description = req.Description
+}
+
+// CreateEditionRequestToModel converts CreateEditionRequest DTO to an Edition model of a book
+func CreateEditionRequestToModel(bookID uint, req CreateEditionRequest) models.Edition {
+	return models.Edition{
+		BookID:      bookID,
+		ISBN:        strings.TrimSpace(req.ISBN),
+		ISBN13:      normalizeISBN(req.ISBN),
+		Format:      req.Format,
+		Language:    req.Language,
+		PageCount:   req.PageCount,
+		PublisherID: req.PublisherID,
+	}
+}
+
+// ToUpdateEditionRequest converts an Edition model to the UpdateEditionRequest DTO that would leave it unchanged
+func ToUpdateEditionRequest(edition models.Edition) UpdateEditionRequest {
+	return UpdateEditionRequest{
+		ISBN:        edition.ISBN,
+		Format:      edition.Format,
+		Language:    edition.Language,
+		PageCount:   edition.PageCount,
+		PublisherID: edition.PublisherID,
+	}
+}
+
+// UpdateEditionModelFromRequest replaces the fields of Edition model with UpdateEditionRequest DTO
+func UpdateEditionModelFromRequest(edition *models.Edition, req UpdateEditionRequest) {
+	edition.ISBN = strings.TrimSpace(req.ISBN)
+	edition.ISBN13 = normalizeISBN(req.ISBN)
+	edition.Format = req.Format
+	edition.Language = req.Language
+	edition.PageCount = req.PageCount
+	edition.PublisherID = req.PublisherID
+	edition.Publisher = nil
+}
+
+// CreateCopyRequestToModel converts CreateCopyRequest DTO to a Copy model of a
+// book, available unless the request says otherwise
+func CreateCopyRequestToModel(bookID uint, req CreateCopyRequest) models.Copy {
+	status := req.Status
+	if status == "" {
+		status = models.CopyAvailable
 	}
-	if req.AuthorID != 0 {
-		book.AuthorID = req.AuthorID
+	return models.Copy{
+		BookID:          bookID,
+		Barcode:         strings.TrimSpace(req.Barcode),
+		ShelfLocation:   strings.TrimSpace(req.ShelfLocation),
+		Condition:       req.Condition,
+		AcquisitionDate: req.AcquisitionDate,
+		Status:          status,
+	}
+}
+
+// ToUpdateCopyRequest converts a Copy model to the UpdateCopyRequest DTO that would leave it unchanged
+func ToUpdateCopyRequest(bookCopy models.Copy) UpdateCopyRequest {
+	return UpdateCopyRequest{
+		Barcode:         bookCopy.Barcode,
+		ShelfLocation:   bookCopy.ShelfLocation,
+		Condition:       bookCopy.Condition,
+		AcquisitionDate: bookCopy.AcquisitionDate,
+		Status:          bookCopy.Status,
+	}
+}
+
+// UpdateCopyModelFromRequest replaces the fields of Copy model with UpdateCopyRequest DTO
+func UpdateCopyModelFromRequest(bookCopy *models.Copy, req UpdateCopyRequest) {
+	bookCopy.Barcode = strings.TrimSpace(req.Barcode)
+	bookCopy.ShelfLocation = strings.TrimSpace(req.ShelfLocation)
+	bookCopy.Condition = req.Condition
+	bookCopy.AcquisitionDate = req.AcquisitionDate
+	bookCopy.Status = req.Status
+}
+
+// CreatePublisherRequestToModel converts CreatePublisherRequest DTO to Publisher model
+func CreatePublisherRequestToModel(req CreatePublisherRequest) models.Publisher {
+	return models.Publisher{Name: req.Name}
+}
+
+// ToUpdatePublisherRequest converts a Publisher model to the UpdatePublisherRequest DTO that would leave it unchanged
+func ToUpdatePublisherRequest(publisher models.Publisher) UpdatePublisherRequest {
+	return UpdatePublisherRequest{Name: publisher.Name}
+}
+
+// UpdatePublisherModelFromRequest replaces the fields of Publisher model with UpdatePublisherRequest DTO
+func UpdatePublisherModelFromRequest(publisher *models.Publisher, req UpdatePublisherRequest) {
+	publisher.Name = req.Name
+}
+
+// CreateTagRequestToModel converts CreateTagRequest DTO to Tag model
+func CreateTagRequestToModel(req CreateTagRequest) models.Tag {
+	return models.Tag{Name: req.Name}
+}
+
+// ToUpdateTagRequest converts a Tag model to the UpdateTagRequest DTO that would leave it unchanged
+func ToUpdateTagRequest(tag models.Tag) UpdateTagRequest {
+	return UpdateTagRequest{Name: tag.Name}
+}
+
+// UpdateTagModelFromRequest replaces the fields of Tag model with UpdateTagRequest DTO
+func UpdateTagModelFromRequest(tag *models.Tag, req UpdateTagRequest) {
+	tag.Name = req.Name
+}
+
+// CreateSubjectRequestToModel converts CreateSubjectRequest DTO to Subject model
+func CreateSubjectRequestToModel(req CreateSubjectRequest) models.Subject {
+	return models.Subject{
+		ParentID: req.ParentID,
+		Code:     req.Code,
+		Label:    req.Label,
 	}
-	if req.ISBN != "" {
-		book.ISBN = req.ISBN
+}
+
+// ToUpdateSubjectRequest converts a Subject model to the UpdateSubjectRequest DTO that would leave it unchanged
+func ToUpdateSubjectRequest(subject models.Subject) UpdateSubjectRequest {
+	return UpdateSubjectRequest{
+		Code:  subject.Code,
+		Label: subject.Label,
 	}
-	if req.PublicationYear != 0 {
-		book.PublicationYear = req.PublicationYear
+}
+
+// UpdateSubjectModelFromRequest replaces the fields of Subject model with UpdateSubjectRequest DTO
+func UpdateSubjectModelFromRequest(subject *models.Subject, req UpdateSubjectRequest) {
+	subject.Code = req.Code
+	subject.Label = req.Label
+}
+
+// CreateSeriesRequestToModel converts CreateSeriesRequest DTO to Series model
+func CreateSeriesRequestToModel(req CreateSeriesRequest) models.Series {
+	return models.Series{
+		Name:        req.Name,
+		Description: req.Description,
 	}
-	if req.Description != "" {
-		book.Description = req.Description
+}
+
+// ToUpdateSeriesRequest converts a Series model to the UpdateSeriesRequest DTO that would leave it unchanged
+func ToUpdateSeriesRequest(series models.Series) UpdateSeriesRequest {
+	return UpdateSeriesRequest{
+		Name:        series.Name,
+		Description: series.Description,
 	}
 }
 
-// CreateReviewRequestToModel converts CreateReviewRequest DTO to Review model
-func CreateReviewRequestToModel(req CreateReviewRequest, bookID uint) models.Review {
+// UpdateSeriesModelFromRequest replaces the fields of Series model with UpdateSeriesRequest DTO
+func UpdateSeriesModelFromRequest(series *models.Series, req UpdateSeriesRequest) {
+	series.Name = req.Name
+	series.Description = req.Description
+}
+
+// CreateReviewRequestToModel converts CreateReviewRequest DTO to Review model posted by userID
+func CreateReviewRequestToModel(req CreateReviewRequest, bookID uint, userID *uint) models.Review {
 	return models.Review{
 		Rating:     req.Rating,
 		Comment:    req.Comment,
 		DatePosted: time.Now().Format("2006-01-02"),
 		BookID:     bookID,
+		UserID:     userID,
 	}
 }
 
-// UpdateReviewModelFromRequest updates Review model from UpdateReviewRequest DTO
+// ToUpdateReviewRequest converts a Review model to the UpdateReviewRequest DTO that would leave it unchanged
+func ToUpdateReviewRequest(review models.Review) UpdateReviewRequest {
+	return UpdateReviewRequest{
+		Rating:  review.Rating,
+		Comment: review.Comment,
+	}
+}
+
+// UpdateReviewModelFromRequest replaces the fields of Review model with UpdateReviewRequest DTO
 func UpdateReviewModelFromRequest(review *models.Review, req UpdateReviewRequest) {
-	if req.Rating != 0 {
-		review.Rating = req.Rating
+	review.Rating = req.Rating
+	review.Comment = req.Comment
+}
+
+// PrimaryAuthorID returns the first contributor in the author role, 0 when there is none
+func PrimaryAuthorID(contributors []ContributorRequest) uint {
+	for _, contributor := range contributors {
+		if contributor.Role == models.ContributorAuthor {
+			return contributor.AuthorID
+		}
+	}
+	return 0
+}
+
+// contributorsFromRequest converts the contributors of a book request in
+// credit order, or its author_id when it has none, and returns the primary author
+func contributorsFromRequest(authorID uint, reqs []ContributorRequest) ([]models.BookContributor, uint) {
+	if len(reqs) == 0 {
+		return []models.BookContributor{{AuthorID: authorID, Role: models.ContributorAuthor}}, authorID
+	}
+
+	contributors := make([]models.BookContributor, len(reqs))
+	for i, req := range reqs {
+		contributors[i] = models.BookContributor{AuthorID: req.AuthorID, Role: req.Role, Position: i}
+	}
+	return contributors, PrimaryAuthorID(reqs)
+}
+
+// genresFromIDs converts the genre IDs of a book request to unloaded genres
+func genresFromIDs(ids []uint) []models.Genre {
+	genres := make([]models.Genre, len(ids))
+	for i, id := range ids {
+		genres[i] = models.Genre{ID: id}
+	}
+	return genres
+}
+
+// tagsFromIDs converts the tag IDs of a book request to unloaded tags
+func tagsFromIDs(ids []uint) []models.Tag {
+	tags := make([]models.Tag, len(ids))
+	for i, id := range ids {
+		tags[i] = models.Tag{ID: id}
 	}
-	if req.Comment != "" {
-		review.Comment = req.Comment
+	return tags
+}
+
+// subjectsFromIDs converts the subject IDs of a book request to unloaded subjects
+func subjectsFromIDs(ids []uint) []models.Subject {
+	subjects := make([]models.Subject, len(ids))
+	for i, id := range ids {
+		subjects[i] = models.Subject{ID: id}
+	}
+	return subjects
+}
+
+// normalizeISBN returns the ISBN-13 form of a validated ISBN, nil if it is invalid
+func normalizeISBN(value string) *string {
+	normalized, err := isbn.Normalize(value)
+	if err != nil {
+		return nil
+	}
+	return &normalized
+}
+
+// deletedAt returns when a soft deleted record was deleted, nil if it is not deleted
+func deletedAt(value gorm.DeletedAt) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
 	}
+	return *value
 }
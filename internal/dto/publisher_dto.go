@@ -0,0 +1,34 @@
+package dto
+
+// CreatePublisherRequest represents the request body for creating a publisher
+type CreatePublisherRequest struct {
+	Name string `json:"name" binding:"required,max=200" example:"Bloomsbury"`
+}
+
+// UpdatePublisherRequest represents the full replacement of a publisher sent with PUT.
+// PATCH requests are applied to the current publisher in this form.
+type UpdatePublisherRequest struct {
+	Name string `json:"name" binding:"required,max=200" example:"Bloomsbury"`
+}
+
+// PublisherResponse represents the response body for publisher information
+type PublisherResponse struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"Bloomsbury"`
+}
+
+// PublisherDetailResponse includes the number of editions by the publisher
+type PublisherDetailResponse struct {
+	ID           uint   `json:"id" example:"1"`
+	Name         string `json:"name" example:"Bloomsbury"`
+	EditionCount int64  `json:"edition_count" example:"12"`
+}
+
+// PaginatedPublishersResponse represents paginated publisher list response
+type PaginatedPublishersResponse struct {
+	Data       []PublisherDetailResponse `json:"data"`
+	Total      int64                     `json:"total" example:"100"`
+	Page       int                       `json:"page" example:"1"`
+	PageSize   int                       `json:"page_size" example:"10"`
+	TotalPages int                       `json:"total_pages" example:"10"`
+}
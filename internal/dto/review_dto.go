@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // CreateReviewRequest represents the request body for creating a review
 type CreateReviewRequest struct {
 	Rating  int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
@@ -7,17 +9,28 @@ type CreateReviewRequest struct {
 	BookID  uint   `json:"book_id" binding:"required" example:"1"`
 }
 
-// UpdateReviewRequest represents the request body for updating a review
+// UpdateReviewRequest represents the full replacement of a review sent with PUT.
+// PATCH requests are applied to the current review in this form.
 type UpdateReviewRequest struct {
-	Rating  int    `json:"rating" binding:"min=1,max=5" example:"4"`
+	Rating  int    `json:"rating" binding:"required,min=1,max=5" example:"4"`
 	Comment string `json:"comment" example:"Very good book, but a bit too long."`
 }
 
 // ReviewResponse represents the response body for review information
 type ReviewResponse struct {
-	ID         uint   `json:"id" example:"1"`
-	Rating     int    `json:"rating" example:"5"`
-	Comment    string `json:"comment" example:"A masterpiece of fantasy literature!"`
-	DatePosted string `json:"date_posted" example:"2025-03-09"`
-	BookID     uint   `json:"book_id" example:"1"`
+	ID         uint       `json:"id" example:"1"`
+	Rating     int        `json:"rating" example:"5"`
+	Comment    string     `json:"comment" example:"A masterpiece of fantasy literature!"`
+	DatePosted string     `json:"date_posted" example:"2025-03-09"`
+	BookID     uint       `json:"book_id" example:"1"`
+	UserID     *uint      `json:"user_id,omitempty" example:"1"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" example:"2025-03-10T02:00:00Z"`
+}
+
+// CursorReviewsResponse represents a cursor paginated review list response
+type CursorReviewsResponse struct {
+	Data       []ReviewResponse `json:"data"`
+	NextCursor string           `json:"next_cursor,omitempty" example:"eyJ2IjpbIjEwIl19"`
+	PrevCursor string           `json:"prev_cursor,omitempty" example:"eyJ2IjpbIjEiXSwiYiI6dHJ1ZX0"`
+	PageSize   int              `json:"page_size" example:"10"`
 }
@@ -0,0 +1,18 @@
+package dto
+
+// SearchResultResponse is a single search hit, either a book or an author
+type SearchResultResponse struct {
+	Type       string            `json:"type" example:"book" enums:"book,author"`
+	ID         uint              `json:"id" example:"1"`
+	Score      float64           `json:"score" example:"0.61"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+	Book       *BookResponse     `json:"book,omitempty"`
+	Author     *AuthorResponse   `json:"author,omitempty"`
+}
+
+// SearchResponse represents the search results ordered by relevance
+type SearchResponse struct {
+	Query   string                 `json:"query" example:"oguz atay"`
+	Results []SearchResultResponse `json:"results"`
+	Total   int                    `json:"total" example:"2"`
+}
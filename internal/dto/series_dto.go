@@ -0,0 +1,64 @@
+package dto
+
+// CreateSeriesRequest represents the request body for creating a series
+type CreateSeriesRequest struct {
+	Name        string `json:"name" binding:"required,max=200" example:"The Stormlight Archive"`
+	Description string `json:"description" binding:"max=1000" example:"Epic fantasy set on the world of Roshar"`
+}
+
+// UpdateSeriesRequest represents the full replacement of a series sent with PUT.
+// PATCH requests are applied to the current series in this form.
+type UpdateSeriesRequest struct {
+	Name        string `json:"name" binding:"required,max=200" example:"The Stormlight Archive"`
+	Description string `json:"description" binding:"max=1000" example:"Epic fantasy set on the world of Roshar"`
+}
+
+// SeriesResponse represents the response body for series information
+type SeriesResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	Name        string `json:"name" example:"The Stormlight Archive"`
+	Description string `json:"description,omitempty" example:"Epic fantasy set on the world of Roshar"`
+}
+
+// SeriesSummaryResponse includes the number of books in the series
+type SeriesSummaryResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	Name        string `json:"name" example:"The Stormlight Archive"`
+	Description string `json:"description,omitempty" example:"Epic fantasy set on the world of Roshar"`
+	BookCount   int64  `json:"book_count" example:"5"`
+}
+
+// SeriesDetailResponse includes the books of the series in reading order
+type SeriesDetailResponse struct {
+	ID          uint           `json:"id" example:"1"`
+	Name        string         `json:"name" example:"The Stormlight Archive"`
+	Description string         `json:"description,omitempty" example:"Epic fantasy set on the world of Roshar"`
+	Books       []BookResponse `json:"books"`
+}
+
+// PaginatedSeriesResponse represents paginated series list response
+type PaginatedSeriesResponse struct {
+	Data       []SeriesSummaryResponse `json:"data"`
+	Total      int64                   `json:"total" example:"100"`
+	Page       int                     `json:"page" example:"1"`
+	PageSize   int                     `json:"page_size" example:"10"`
+	TotalPages int                     `json:"total_pages" example:"10"`
+}
+
+// BookSeriesResponse places a book in its series, with links to the books
+// read before and after it
+type BookSeriesResponse struct {
+	ID       uint                 `json:"id" example:"1"`
+	Name     string               `json:"name" example:"The Stormlight Archive"`
+	Position float64              `json:"position" example:"2.5"`
+	Previous *SeriesEntryResponse `json:"previous,omitempty"`
+	Next     *SeriesEntryResponse `json:"next,omitempty"`
+}
+
+// SeriesEntryResponse is a neighbouring book in a series
+type SeriesEntryResponse struct {
+	ID       uint    `json:"id" example:"7"`
+	Title    string  `json:"title" example:"Words of Radiance"`
+	Position float64 `json:"position" example:"2"`
+	Link     string  `json:"link" example:"/api/v1/books/7"`
+}
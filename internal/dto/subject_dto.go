@@ -0,0 +1,57 @@
+package dto
+
+// CreateSubjectRequest represents the request body for creating a subject,
+// at the top level or under parent_id
+type CreateSubjectRequest struct {
+	Code     string `json:"code" binding:"required,max=50" example:"823.914"`
+	Label    string `json:"label" binding:"required,max=200" example:"English fiction, 1945-1999"`
+	ParentID *uint  `json:"parent_id,omitempty" binding:"omitempty,min=1" example:"12"`
+}
+
+// UpdateSubjectRequest represents the full replacement of a subject sent with PUT.
+// PATCH requests are applied to the current subject in this form. Subjects
+// change parent with MoveSubjectRequest.
+type UpdateSubjectRequest struct {
+	Code  string `json:"code" binding:"required,max=50" example:"823.914"`
+	Label string `json:"label" binding:"required,max=200" example:"English fiction, 1945-1999"`
+}
+
+// MoveSubjectRequest represents the new parent of a subject and its
+// descendants, null or left out to move it to the top level
+type MoveSubjectRequest struct {
+	ParentID *uint `json:"parent_id" binding:"omitempty,min=1" example:"3"`
+}
+
+// SubjectResponse represents the response body for subject information
+type SubjectResponse struct {
+	ID       uint   `json:"id" example:"14"`
+	Code     string `json:"code" example:"823.914"`
+	Label    string `json:"label" example:"English fiction, 1945-1999"`
+	ParentID *uint  `json:"parent_id" example:"12"`
+}
+
+// SubjectDetailResponse includes the ancestors of the subject, from the top level down
+type SubjectDetailResponse struct {
+	ID        uint              `json:"id" example:"14"`
+	Code      string            `json:"code" example:"823.914"`
+	Label     string            `json:"label" example:"English fiction, 1945-1999"`
+	ParentID  *uint             `json:"parent_id" example:"12"`
+	Ancestors []SubjectResponse `json:"ancestors"`
+}
+
+// SubjectTreeResponse represents a subject with its descendants
+type SubjectTreeResponse struct {
+	ID       uint                  `json:"id" example:"12"`
+	Code     string                `json:"code" example:"823"`
+	Label    string                `json:"label" example:"English fiction"`
+	Children []SubjectTreeResponse `json:"children"`
+}
+
+// PaginatedSubjectsResponse represents paginated subject list response
+type PaginatedSubjectsResponse struct {
+	Data       []SubjectResponse `json:"data"`
+	Total      int64             `json:"total" example:"100"`
+	Page       int               `json:"page" example:"1"`
+	PageSize   int               `json:"page_size" example:"10"`
+	TotalPages int               `json:"total_pages" example:"10"`
+}
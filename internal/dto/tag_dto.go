@@ -0,0 +1,34 @@
+package dto
+
+// CreateTagRequest represents the request body for creating a tag
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"time travel"`
+}
+
+// UpdateTagRequest represents the full replacement of a tag sent with PUT.
+// PATCH requests are applied to the current tag in this form.
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"time travel"`
+}
+
+// TagResponse represents the response body for tag information
+type TagResponse struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"time travel"`
+}
+
+// TagDetailResponse includes the number of books with the tag
+type TagDetailResponse struct {
+	ID        uint   `json:"id" example:"1"`
+	Name      string `json:"name" example:"time travel"`
+	BookCount int64  `json:"book_count" example:"4"`
+}
+
+// PaginatedTagsResponse represents paginated tag list response
+type PaginatedTagsResponse struct {
+	Data       []TagDetailResponse `json:"data"`
+	Total      int64               `json:"total" example:"100"`
+	Page       int                 `json:"page" example:"1"`
+	PageSize   int                 `json:"page_size" example:"10"`
+	TotalPages int                 `json:"total_pages" example:"10"`
+}
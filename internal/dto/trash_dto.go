@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// PurgeResponse reports how many soft deleted records were permanently removed
+type PurgeResponse struct {
+	DeletedBefore time.Time `json:"deleted_before" example:"2025-02-07T12:00:00Z"`
+	Books         int64     `json:"books" example:"3"`
+	Reviews       int64     `json:"reviews" example:"12"`
+	Authors       int64     `json:"authors" example:"1"`
+}
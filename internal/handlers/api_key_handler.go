@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler serves the admin endpoints for managing API keys
+type APIKeyHandler struct {
+	apiKeys repository.APIKeyRepository
+}
+
+// NewAPIKeyHandler creates an APIKeyHandler backed by the given repository
+func NewAPIKeyHandler(apiKeys repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{apiKeys: apiKeys}
+}
+
+// GetAPIKeys godoc
+// @Summary Get all API keys
+// @Description Get a paginated list of active API keys. Secrets are never returned.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedAPIKeysResponse
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	keys, totalCount, err := h.apiKeys.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	keyResponses := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		keyResponses[i] = dto.ToAPIKeyResponse(key)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedAPIKeysResponse{
+		Data:       keyResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create an API key with the given scopes. The key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key body dto.CreateAPIKeyRequest true "Key name and scopes"
+// @Success 201 {object} dto.APIKeySecretResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Admin routes refuse API keys, so there is always a user here
+	userID, _ := middleware.CurrentUserID(c)
+
+	scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+	key := models.APIKey{
+		Name:        req.Name,
+		Scopes:      strings.Join(scopes, " "),
+		CreatedByID: userID,
+	}
+
+	secret, err := h.assignSecret(&key)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.apiKeys.Create(&key); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIKeySecretResponse{APIKeyResponse: dto.ToAPIKeyResponse(key), Key: secret})
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate API key
+// @Description Replace the secret of an API key, keeping its name and scopes. The old key stops working immediately.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID" minimum(1)
+// @Success 200 {object} dto.APIKeySecretResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "API key not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	key, err := h.apiKeys.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	secret, err := h.assignSecret(key)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	key.LastUsedAt = nil
+
+	if err := h.apiKeys.Update(key); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIKeySecretResponse{APIKeyResponse: dto.ToAPIKeyResponse(*key), Key: secret})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key so it can no longer be used
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID" minimum(1)
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "API key not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	if _, err := h.apiKeys.GetByID(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.apiKeys.Delete(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// assignSecret generates a new key for an API key model and returns it in plain text
+func (h *APIKeyHandler) assignSecret(key *models.APIKey) (string, error) {
+	secret, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	key.Prefix = prefix
+	key.KeyHash = auth.HashAPIKey(secret)
+	return secret, nil
+}
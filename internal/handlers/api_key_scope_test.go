@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyScopes(t *testing.T) {
+	s := newTestServer(t)
+	librarian := s.user(t, models.RoleLibrarian)
+	authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+	bookPath := fmt.Sprintf("/api/v1/books/%d", s.created(t, "/api/v1/books", bookBody(authorID, "0-7475-3269-9"), "Authorization", librarian))
+
+	readOnly, _ := s.apiKey(t, auth.ScopeBooksRead, auth.ScopeAuthorsRead)
+	writer, _ := s.apiKey(t, auth.ScopeAuthorsWrite)
+	revoked, revokedKey := s.apiKey(t, auth.ScopeAuthorsWrite, auth.ScopeBooksRead)
+	if err := s.store.APIKeys().Delete(revokedKey.ID); err != nil {
+		t.Fatalf("revoke API key: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		key        string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "read with scope", method: http.MethodGet, path: bookPath, key: readOnly, wantStatus: http.StatusOK},
+		{name: "read without scope", method: http.MethodGet, path: bookPath, key: writer, wantStatus: http.StatusForbidden, wantCode: "insufficient_scope"},
+		{name: "write with scope", method: http.MethodPost, path: "/api/v1/authors", body: authorBody("New"), key: writer, wantStatus: http.StatusCreated},
+		{name: "write without scope", method: http.MethodPost, path: "/api/v1/authors", body: authorBody("New"), key: readOnly, wantStatus: http.StatusForbidden, wantCode: "insufficient_scope"},
+		{name: "write with the scope of another resource", method: http.MethodPost, path: "/api/v1/books", body: bookBody(authorID, "978-0-306-40615-7"), key: writer, wantStatus: http.StatusForbidden, wantCode: "insufficient_scope"},
+		{name: "review without scope", method: http.MethodPost, path: bookPath + "/reviews", body: reviewBody(1, 4), key: writer, wantStatus: http.StatusForbidden, wantCode: "insufficient_scope"},
+		{name: "revoked key", method: http.MethodGet, path: bookPath, key: revoked, wantStatus: http.StatusUnauthorized, wantCode: "invalid_api_key"},
+		{name: "unknown key", method: http.MethodGet, path: bookPath, key: auth.APIKeyPrefix + "unknown", wantStatus: http.StatusUnauthorized, wantCode: "invalid_api_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(t, tt.method, tt.path, tt.body, "X-API-Key", tt.key)
+			if w.Code != tt.wantStatus || errorCode(w) != tt.wantCode {
+				t.Errorf("%s %s = %d %q, want %d %q: %s", tt.method, tt.path, w.Code, errorCode(w), tt.wantStatus, tt.wantCode, w.Body)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthorDeleteNeedsBooksScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		scopes     []string
+		wantStatus int
+	}{
+		{name: "cascade without books:write", query: "?cascade=true", scopes: []string{auth.ScopeAuthorsWrite}, wantStatus: http.StatusForbidden},
+		{name: "reassign without books:write", query: "?reassign_to=%d", scopes: []string{auth.ScopeAuthorsWrite}, wantStatus: http.StatusForbidden},
+		{name: "cascade with books:write", query: "?cascade=true", scopes: []string{auth.ScopeAuthorsWrite, auth.ScopeBooksWrite}, wantStatus: http.StatusNoContent},
+		{name: "reassign with books:write", query: "?reassign_to=%d", scopes: []string{auth.ScopeAuthorsWrite, auth.ScopeBooksWrite}, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			librarian := s.user(t, models.RoleLibrarian)
+			authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+			otherID := s.created(t, "/api/v1/authors", authorBody("Other"), "Authorization", librarian)
+			s.created(t, "/api/v1/books", bookBody(authorID, "0-7475-3269-9"), "Authorization", librarian)
+			key, _ := s.apiKey(t, tt.scopes...)
+
+			query := tt.query
+			if strings.Contains(query, "%d") {
+				query = fmt.Sprintf(query, otherID)
+			}
+			path := fmt.Sprintf("/api/v1/authors/%d", authorID)
+			w := s.do(t, http.MethodDelete, path+query, nil, "X-API-Key", key)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("DELETE = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			refused := tt.wantStatus == http.StatusForbidden
+			if refused && !strings.Contains(w.Body.String(), auth.ScopeBooksWrite) {
+				t.Errorf("refusal %s does not name the books:write scope", w.Body)
+			}
+
+			// A refused delete leaves the author in place
+			if got := s.do(t, http.MethodGet, path, nil).Code; (got == http.StatusOK) != refused {
+				t.Errorf("GET author after DELETE = %d", got)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthorDeleteWithoutBooks(t *testing.T) {
+	s := newTestServer(t)
+	librarian := s.user(t, models.RoleLibrarian)
+	authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+	key, _ := s.apiKey(t, auth.ScopeAuthorsWrite)
+
+	// A plain delete only needs authors:write
+	w := s.do(t, http.MethodDelete, fmt.Sprintf("/api/v1/authors/%d", authorID), nil, "X-API-Key", key)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("DELETE = %d, want 204: %s", w.Code, w.Body)
+	}
+}
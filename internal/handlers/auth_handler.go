@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Credential errors, deliberately vague about which part was wrong
+var (
+	errInvalidCredentials  = apperr.Unauthorized("invalid_credentials", "Invalid email or password")
+	errInvalidRefreshToken = apperr.Unauthorized("invalid_refresh_token", "Invalid or expired refresh token")
+)
+
+// dummyPasswordHash is a bcrypt hash, at the default cost, that logins with an
+// unknown email are checked against so they take as long as a wrong password
+// and response times do not reveal which accounts exist
+const dummyPasswordHash = "$2a$10$Rjl3hlGPaZ74akl6uLxkFedv.aJcDrQXQO8JVhew7yujeBShXNVHu"
+
+// AuthHandler serves the account and token endpoints
+type AuthHandler struct {
+	users  repository.UserRepository
+	tokens *auth.TokenService
+}
+
+// NewAuthHandler creates an AuthHandler backed by the given repository and token service
+func NewAuthHandler(users repository.UserRepository, tokens *auth.TokenService) *AuthHandler {
+	return &AuthHandler{users: users, tokens: tokens}
+}
+
+// Register godoc
+// @Summary Register a user
+// @Description Create a user account and return an access and refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body dto.RegisterRequest true "Account details"
+// @Success 201 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 409 {object} dto.ProblemResponse "Email already registered"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	user := models.User{
+		Email:        normalizeEmail(req.Email),
+		Name:         req.Name,
+		PasswordHash: hash,
+		Role:         models.RoleUser,
+	}
+
+	if err := h.users.Create(&user); err != nil {
+		if apperr.KindOf(err) == apperr.KindConflict {
+			err = apperr.Conflict("email_taken", "Email already registered")
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusCreated, user)
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Exchange email and password for an access and refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LoginRequest true "Credentials"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Invalid email or password"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	user, err := h.users.GetByEmail(normalizeEmail(req.Email))
+	if err != nil {
+		if apperr.KindOf(err) == apperr.KindNotFound {
+			auth.CheckPassword(dummyPasswordHash, req.Password)
+		}
+		abortWithError(c, replaceNotFound(err, errInvalidCredentials))
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, req.Password) {
+		abortWithError(c, errInvalidCredentials)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, *user)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access and refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body dto.RefreshRequest true "Refresh token"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Invalid or expired refresh token"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	identity, err := h.tokens.Parse(req.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		abortWithError(c, errInvalidRefreshToken)
+		return
+	}
+
+	// Reload the account, it may have been deleted or given another role
+	user, err := h.users.GetByID(identity.UserID)
+	if err != nil {
+		abortWithError(c, replaceNotFound(err, errInvalidRefreshToken))
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, *user)
+}
+
+// respondWithTokens issues a token pair for user and writes the AuthResponse
+func (h *AuthHandler) respondWithTokens(c *gin.Context, status int, user models.User) {
+	tokens, err := h.tokens.Issue(auth.Identity{UserID: user.ID, Role: user.Role})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(status, dto.AuthResponse{
+		User:         dto.ToUserResponse(user),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	})
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
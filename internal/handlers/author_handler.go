@@ -1,60 +1,197 @@
 package handlers
 
 import (
+	"fmt"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/auth"
 	"go-rest-api/internal/dto"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
 	"go-rest-api/internal/repository"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Author errors reported in terms of the request
+var (
+	errAuthorHasBooks   = apperr.Conflict("author_has_books", "Author still has books, delete them with cascade=true or move them with reassign_to")
+	errAuthorNotDeleted = apperr.Conflict("author_not_deleted", "Author is not deleted")
+	errReassignToAuthor = apperr.Validation("reassign_author_not_found", "The author to reassign the books to does not exist")
+	errBooksScope       = apperr.Forbidden("insufficient_scope", "API key needs the books:write scope to delete or reassign an author's books")
+)
+
+// AuthorHandler serves the author endpoints
+type AuthorHandler struct {
+	authors repository.AuthorRepository
+}
+
+// NewAuthorHandler creates an AuthorHandler backed by the given repository
+func NewAuthorHandler(authors repository.AuthorRepository) *AuthorHandler {
+	return &AuthorHandler{authors: authors}
+}
+
 // GetAuthors godoc
 // @Summary Get all authors
-// @Description Get a list of all authors with their books
+// @Description Get a paginated list of authors with their book counts. Books are only included with include=books.
 // @Tags authors
 // @Accept json
 // @Produce json
-// @Success 200 {array} dto.AuthorDetailResponse
-// @Failure 500 {object} map[string]string "Server error"
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Param include query string false "Set to books to embed each author's books" Enums(books)
+// @Param pagination query string false "Set to cursor for keyset pagination by ID, the response is then a dto.CursorAuthorsResponse" Enums(page, cursor)
+// @Param cursor query string false "Opaque next_cursor or prev_cursor from a previous cursor page, implies pagination=cursor"
+// @Param include_deleted query bool false "Also list deleted authors, which have a deleted_at. Admins only."
+// @Success 200 {object} dto.PaginatedAuthorsResponse "Returns paginated authors data"
+// @Success 200 {object} dto.CursorAuthorsResponse "Returns cursor paginated authors when pagination=cursor"
+// @Failure 400 {object} dto.ProblemResponse "Invalid include or cursor parameter"
+// @Failure 401 {object} dto.ProblemResponse "include_deleted without authentication"
+// @Failure 403 {object} dto.ProblemResponse "include_deleted by a caller that is not an admin"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/authors [get]
-func GetAuthors(c *gin.Context) {
-	authors, err := repository.GetAllAuthors()
+func (h *AuthorHandler) GetAuthors(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	var filter repository.AuthorFilter
+	filter.IncludeBooks, err = parseIncludeBooks(c)
+	if err != nil {
+		abortWithError(c, invalidQuery(err))
+		return
+	}
+
+	filter.IncludeDeleted, err = parseIncludeDeleted(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if cursorPagination(c) {
+		h.getAuthorsByCursor(c, filter, pageSize)
+		return
+	}
+
+	authors, totalCount, err := h.authors.GetAll(filter, page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response, err := h.authorListResponses(authors)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedAuthorsResponse{
+		Data:       response,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// getAuthorsByCursor serves GetAuthors in cursor mode
+func (h *AuthorHandler) getAuthorsByCursor(c *gin.Context, filter repository.AuthorFilter, pageSize int) {
+	cursor, err := parseCursor(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	authors, page, err := h.authors.GetAllByCursor(filter, cursor, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response, err := h.authorListResponses(authors)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
 
-	// Convert to DTOs
+	c.JSON(http.StatusOK, dto.CursorAuthorsResponse{
+		Data:       response,
+		NextCursor: encodeCursor(page.Next),
+		PrevCursor: encodeCursor(page.Prev),
+		PageSize:   pageSize,
+	})
+}
+
+// authorListResponses converts a page of authors to DTOs with their book counts
+func (h *AuthorHandler) authorListResponses(authors []models.Author) ([]dto.AuthorDetailResponse, error) {
+	ids := make([]uint, len(authors))
+	for i, author := range authors {
+		ids[i] = author.ID
+	}
+
+	counts, err := h.authors.CountBooks(ids)
+	if err != nil {
+		return nil, err
+	}
+
 	response := make([]dto.AuthorDetailResponse, len(authors))
 	for i, author := range authors {
 		response[i] = dto.ToAuthorDetailResponse(author)
+		response[i].BookCount = counts[author.ID]
 	}
+	return response, nil
+}
 
-	c.JSON(http.StatusOK, response)
+// parseIncludeBooks reads the include parameter, books is the only supported value
+func parseIncludeBooks(c *gin.Context) (bool, error) {
+	includeBooks := false
+	for _, value := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(value) {
+		case "":
+		case "books":
+			includeBooks = true
+		default:
+			return false, fmt.Errorf("unknown include %q, expected books", value)
+		}
+	}
+	return includeBooks, nil
 }
 
 // GetAuthor godoc
 // @Summary Get author by ID
-// @Description Get an author's details by ID
+// @Description Get an author's details by ID, with the books the author is credited on grouped by role in books_by_role
 // @Tags authors
 // @Accept json
 // @Produce json
 // @Param id path int true "Author ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} dto.AuthorDetailResponse
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 404 {object} map[string]string "Author not found"
+// @Header 200 {string} ETag "Changes with the author and its books"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Author not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/authors/{id} [get]
-func GetAuthor(c *gin.Context) {
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	author, err := repository.GetAuthorByID(uint(id))
+	author, err := h.authors.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, authorETag(*author)) {
 		return
 	}
 
@@ -63,27 +200,31 @@ func GetAuthor(c *gin.Context) {
 
 // CreateAuthor godoc
 // @Summary Create new author
-// @Description Create a new author
+// @Description Create a new author. Requires the librarian or admin role.
 // @Tags authors
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param author body dto.CreateAuthorRequest true "Author object that needs to be added"
 // @Success 201 {object} dto.AuthorResponse
-// @Failure 400 {object} map[string]string "Invalid input"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/authors [post]
-func CreateAuthor(c *gin.Context) {
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
 	var req dto.CreateAuthorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(c, bindingError(err))
 		return
 	}
 
 	// Convert DTO to model
 	author := dto.CreateAuthorRequestToModel(req)
 
-	if err := repository.CreateAuthor(&author); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.authors.Create(&author); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
@@ -91,70 +232,288 @@ func CreateAuthor(c *gin.Context) {
 }
 
 // UpdateAuthor godoc
-// @Summary Update author
-// @Description Update an existing author
+// @Summary Replace author
+// @Description Replace an existing author. Optional fields that are left out are cleared. Requires the librarian or admin role.
 // @Tags authors
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Author ID" minimum(1)
-// @Param author body dto.UpdateAuthorRequest true "Author object that needs to be updated"
+// @Param If-Match header string false "ETag of the author the change is based on"
+// @Param author body dto.UpdateAuthorRequest true "The new state of the author"
 // @Success 200 {object} dto.AuthorResponse
-// @Failure 400 {object} map[string]string "Invalid ID format or request body"
-// @Failure 404 {object} map[string]string "Author not found"
-// @Failure 500 {object} map[string]string "Server error"
+// @Header 200 {string} ETag "ETag of the updated author"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Author not found"
+// @Failure 409 {object} dto.ProblemResponse "The author was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The author has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/authors/{id} [put]
-func UpdateAuthor(c *gin.Context) {
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	author, err := repository.GetAuthorByID(uint(id))
+	author, err := h.authors.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, authorETag(*author)); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	var req dto.UpdateAuthorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveAuthor(c, author, req)
+}
+
+// PatchAuthor godoc
+// @Summary Update author
+// @Description Change some fields of an author with a JSON Merge Patch, where null clears a field, or a JSON Patch. Requires the librarian or admin role.
+// @Tags authors
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Author ID" minimum(1)
+// @Param If-Match header string false "ETag of the author the change is based on"
+// @Param author body dto.UpdateAuthorRequest true "The fields to change"
+// @Success 200 {object} dto.AuthorResponse
+// @Header 200 {string} ETag "ETag of the updated author"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting author"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Author not found"
+// @Failure 409 {object} dto.ProblemResponse "A test operation failed or the author was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The author has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/authors/{id} [patch]
+func (h *AuthorHandler) PatchAuthor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	author, err := h.authors.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, authorETag(*author)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateAuthorRequest
+	if err := bindPatch(c, dto.ToUpdateAuthorRequest(*author), &req); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	h.saveAuthor(c, author, req)
+}
+
+// saveAuthor replaces author with req and responds with the result
+func (h *AuthorHandler) saveAuthor(c *gin.Context, author *models.Author, req dto.UpdateAuthorRequest) {
 	// Update model from DTO
 	dto.UpdateAuthorModelFromRequest(author, req)
 
-	if err := repository.UpdateAuthor(author); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.authors.Update(author); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", authorETag(*author))
 	c.JSON(http.StatusOK, dto.ToAuthorResponse(*author))
 }
 
 // DeleteAuthor godoc
 // @Summary Delete author
-// @Description Delete an author
+// @Description Delete an author. An author credited on books can only be deleted with cascade=true, which also deletes the books the author is the primary author of with their reviews and removes the author's other credits, or with reassign_to, which first moves all of the author's credits to another author. Requires the librarian or admin role, and API keys also need books:write for cascade and reassign_to.
 // @Tags authors
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Author ID" minimum(1)
+// @Param cascade query bool false "Also delete the author's books and their reviews"
+// @Param reassign_to query int false "Move the author's books to this author before deleting" minimum(1)
+// @Param If-Match header string false "ETag of the author the deletion is based on"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, cascade or reassign_to"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Author not found, for conditional deletes"
+// @Failure 409 {object} dto.ProblemResponse "The author still has books, listed under dependents"
+// @Failure 412 {object} dto.ProblemResponse "The author has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/authors/{id} [delete]
-func DeleteAuthor(c *gin.Context) {
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	if err := repository.DeleteAuthor(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Only conditional deletes need the current author
+	if c.GetHeader("If-Match") != "" {
+		author, err := h.authors.GetByID(uint(id))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, authorETag(*author)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	deletion, err := parseAuthorDeletion(c, uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if deletion.Cascade || deletion.ReassignTo != nil {
+		// Changing the books takes the same permission as writing them
+		if identity, _ := middleware.CurrentIdentity(c); identity.IsAPIKey() && !identity.HasScope(auth.ScopeBooksWrite) {
+			abortWithError(c, errBooksScope)
+			return
+		}
+	}
+
+	if deletion.ReassignTo != nil {
+		if _, err := h.authors.GetByID(*deletion.ReassignTo); err != nil {
+			abortWithError(c, replaceNotFound(err, errReassignToAuthor))
+			return
+		}
+	}
+
+	if err := h.authors.Delete(uint(id), deletion); err != nil {
+		// Books are the only records that refer to authors
+		if apperr.From(err).Code == "author_in_use" {
+			err = h.authorHasBooks(uint(id))
+		}
+		abortWithError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// parseAuthorDeletion reads the cascade and reassign_to parameters of DeleteAuthor
+func parseAuthorDeletion(c *gin.Context, id uint) (repository.AuthorDeletion, error) {
+	var deletion repository.AuthorDeletion
+
+	if value := c.Query("cascade"); value != "" {
+		cascade, err := strconv.ParseBool(value)
+		if err != nil {
+			return deletion, invalidQuery(fmt.Errorf("invalid cascade %q, expected true or false", value))
+		}
+		deletion.Cascade = cascade
+	}
+
+	if value := c.Query("reassign_to"); value != "" {
+		to, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || to == 0 {
+			return deletion, invalidQuery(fmt.Errorf("invalid reassign_to %q", value))
+		}
+		if uint(to) == id {
+			return deletion, invalidQuery(fmt.Errorf("reassign_to must be another author"))
+		}
+		authorID := uint(to)
+		deletion.ReassignTo = &authorID
+	}
+
+	if deletion.Cascade && deletion.ReassignTo != nil {
+		return deletion, invalidQuery(fmt.Errorf("cascade and reassign_to cannot be combined"))
+	}
+	return deletion, nil
+}
+
+// authorHasBooks builds the conflict for deleting an author with books, listing
+// each book the author is credited on once
+func (h *AuthorHandler) authorHasBooks(id uint) error {
+	author, err := h.authors.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	var dependents []apperr.Dependent
+	for _, contribution := range author.Contributions {
+		if !slices.ContainsFunc(dependents, func(d apperr.Dependent) bool { return d.ID == contribution.BookID }) {
+			dependents = append(dependents, apperr.Dependent{Resource: "book", ID: contribution.BookID, Name: contribution.Book.Title})
+		}
+	}
+	return apperr.Conflict(errAuthorHasBooks.Code, errAuthorHasBooks.Message, dependents...)
+}
+
+// RestoreAuthor godoc
+// @Summary Restore deleted author
+// @Description Undo the deletion of an author. Requires the librarian or admin role.
+// @Tags authors
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Author ID" minimum(1)
+// @Success 200 {object} dto.AuthorResponse
+// @Header 200 {string} ETag "ETag of the restored author"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Author not found"
+// @Failure 409 {object} dto.ProblemResponse "The author is not deleted"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/authors/{id}/restore [post]
+func (h *AuthorHandler) RestoreAuthor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	_, err = h.authors.GetDeletedByID(uint(id))
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		// Tell a live author apart from one that does not exist
+		if _, liveErr := h.authors.GetByID(uint(id)); liveErr == nil {
+			err = errAuthorNotDeleted
+		}
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.authors.Restore(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	author, err := h.authors.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", authorETag(*author))
+	c.JSON(http.StatusOK, dto.ToAuthorResponse(*author))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"go-rest-api/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestCatalogWriteRoles(t *testing.T) {
+	tests := []struct {
+		role       string
+		wantStatus int
+		wantCode   string
+	}{
+		{role: models.RoleUser, wantStatus: http.StatusForbidden, wantCode: "insufficient_role"},
+		{role: models.RoleLibrarian, wantStatus: http.StatusCreated},
+		{role: models.RoleAdmin, wantStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			s := newTestServer(t)
+			librarian := s.user(t, models.RoleLibrarian)
+			caller := s.user(t, tt.role)
+			authorID := s.created(t, "/api/v1/authors", authorBody("Existing"), "Authorization", librarian)
+			bookID := s.created(t, "/api/v1/books", bookBody(authorID, "0-7475-3269-9"), "Authorization", librarian)
+
+			w := s.do(t, http.MethodPost, "/api/v1/authors", authorBody("New"), "Authorization", caller)
+			if w.Code != tt.wantStatus || errorCode(w) != tt.wantCode {
+				t.Errorf("create author = %d %q, want %d %q", w.Code, errorCode(w), tt.wantStatus, tt.wantCode)
+			}
+			w = s.do(t, http.MethodPost, "/api/v1/books", bookBody(authorID, "978-0-306-40615-7"), "Authorization", caller)
+			if w.Code != tt.wantStatus || errorCode(w) != tt.wantCode {
+				t.Errorf("create book = %d %q, want %d %q", w.Code, errorCode(w), tt.wantStatus, tt.wantCode)
+			}
+
+			wantDelete := http.StatusNoContent
+			if tt.wantStatus != http.StatusCreated {
+				wantDelete = tt.wantStatus
+			}
+			w = s.do(t, http.MethodDelete, fmt.Sprintf("/api/v1/books/%d", bookID), nil, "Authorization", caller)
+			if w.Code != wantDelete || errorCode(w) != tt.wantCode {
+				t.Errorf("delete book = %d %q, want %d %q", w.Code, errorCode(w), wantDelete, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCatalogWriteAnonymous(t *testing.T) {
+	s := newTestServer(t)
+	w := s.do(t, http.MethodPost, "/api/v1/authors", authorBody("New"))
+	if w.Code != http.StatusUnauthorized || errorCode(w) != "authentication_required" {
+		t.Errorf("create author = %d %q, want 401 authentication_required", w.Code, errorCode(w))
+	}
+}
+
+func TestReviewOwnership(t *testing.T) {
+	tests := []struct {
+		name        string
+		caller      string // owner, or the role of another user
+		wantUpdate  int
+		wantDelete  int
+		wantErrCode string
+	}{
+		{name: "owner", caller: "owner", wantUpdate: http.StatusOK, wantDelete: http.StatusNoContent},
+		{name: "other user", caller: models.RoleUser, wantUpdate: http.StatusForbidden, wantDelete: http.StatusForbidden, wantErrCode: "not_review_author"},
+		{name: "librarian", caller: models.RoleLibrarian, wantUpdate: http.StatusForbidden, wantDelete: http.StatusForbidden, wantErrCode: "not_review_author"},
+		{name: "admin", caller: models.RoleAdmin, wantUpdate: http.StatusOK, wantDelete: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			librarian := s.user(t, models.RoleLibrarian)
+			authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+			bookID := s.created(t, "/api/v1/books", bookBody(authorID, "0-7475-3269-9"), "Authorization", librarian)
+			owner := s.user(t, models.RoleUser)
+			reviewID := s.created(t, fmt.Sprintf("/api/v1/books/%d/reviews", bookID), reviewBody(bookID, 3), "Authorization", owner)
+
+			caller := owner
+			if tt.caller != "owner" {
+				caller = s.user(t, tt.caller)
+			}
+			path := fmt.Sprintf("/api/v1/reviews/%d", reviewID)
+
+			w := s.do(t, http.MethodPut, path, reviewBody(bookID, 5), "Authorization", caller)
+			if w.Code != tt.wantUpdate || errorCode(w) != tt.wantErrCode {
+				t.Errorf("update = %d %q, want %d %q", w.Code, errorCode(w), tt.wantUpdate, tt.wantErrCode)
+			}
+			w = s.do(t, http.MethodDelete, path, nil, "Authorization", caller)
+			if w.Code != tt.wantDelete || errorCode(w) != tt.wantErrCode {
+				t.Errorf("delete = %d %q, want %d %q", w.Code, errorCode(w), tt.wantDelete, tt.wantErrCode)
+			}
+		})
+	}
+}
@@ -1,45 +1,114 @@
 package handlers
 
 import (
+	"fmt"
+	"go-rest-api/internal/apperr"
 	"go-rest-api/internal/dto"
+	"go-rest-api/internal/isbn"
+	"go-rest-api/internal/models"
 	"go-rest-api/internal/repository"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Book errors reported in terms of the request
+var (
+	errAuthorDoesNotExist = apperr.Validation("author_not_found", "Author does not exist")
+	errISBNExists         = apperr.Conflict("isbn_exists", "A book or edition with this ISBN already exists")
+	errInvalidISBN        = apperr.Validation("invalid_isbn", "Invalid ISBN, expected an ISBN-10 or ISBN-13")
+	errBookNotDeleted     = apperr.Conflict("book_not_deleted", "Book is not deleted")
+	errAuthorDeleted      = apperr.Conflict("author_deleted", "The book's author is deleted, restore the author first")
+	errGenreDoesNotExist  = apperr.Validation("genre_not_found", "Genre does not exist")
+	errTagDoesNotExist    = apperr.Validation("tag_not_found", "Tag does not exist")
+	errSeriesDoesNotExist = apperr.Validation("series_not_found", "Series does not exist",
+		apperr.FieldError{Field: "series_id", Rule: "exists", Message: "is not an existing series"})
+	errSeriesPositionTaken = apperr.Conflict("series_position_taken", "Another book already has this position in the series")
+)
+
+// BookHandler serves the book endpoints
+type BookHandler struct {
+	books    repository.BookRepository
+	authors  repository.AuthorRepository
+	genres   repository.GenreRepository
+	tags     repository.TagRepository
+	subjects repository.SubjectRepository
+	series   repository.SeriesRepository
+}
+
+// NewBookHandler creates a BookHandler backed by the given repositories
+func NewBookHandler(books repository.BookRepository, authors repository.AuthorRepository, genres repository.GenreRepository, tags repository.TagRepository, subjects repository.SubjectRepository, series repository.SeriesRepository) *BookHandler {
+	return &BookHandler{books: books, authors: authors, genres: genres, tags: tags, subjects: subjects, series: series}
+}
+
 // GetBooks godoc
 // @Summary Get all books
-// @Description Get a list of all books with pagination
+// @Description Get a list of all books with pagination, filtering and sorting
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" minimum(1) default(1)
 // @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Param author_id query int false "Only books by this author" minimum(1)
+// @Param year_gte query int false "Only books published in or after this year"
+// @Param year_lte query int false "Only books published in or before this year"
+// @Param title query string false "Only books whose title contains this text (case-insensitive)"
+// @Param min_rating query number false "Only books with at least this average review rating" minimum(1) maximum(5)
+// @Param genre query string false "Comma separated genre IDs, only books in any of them" example(1,3)
+// @Param genre_match query string false "Set to all to only list books in every genre given by genre" Enums(any, all) default(any)
+// @Param tag query string false "Comma separated tag IDs, only books with any of them" example(2)
+// @Param tag_match query string false "Set to all to only list books with every tag given by tag" Enums(any, all) default(any)
+// @Param subject query int false "Only books filed under this subject or one of its descendants" minimum(1)
+// @Param pagination query string false "Set to cursor for keyset pagination, the response is then a dto.CursorBooksResponse" Enums(page, cursor)
+// @Param cursor query string false "Opaque next_cursor or prev_cursor from a previous cursor page, implies pagination=cursor"
+// @Param sort query string false "Comma separated sort fields, prefix with - for descending. Allowed: id, title, publication_year, created_at, updated_at" example(-publication_year,title)
+// @Param include_deleted query bool false "Also list deleted books, which have a deleted_at. Admins only."
 // @Success 200 {object} dto.PaginatedBooksResponse "Returns paginated books data"
-// @Failure 500 {object} map[string]string "Server error"
+// @Success 200 {object} dto.CursorBooksResponse "Returns cursor paginated books data when pagination=cursor"
+// @Failure 400 {object} dto.ProblemResponse "Invalid filter, sort or cursor parameter, or unknown subject"
+// @Failure 401 {object} dto.ProblemResponse "include_deleted without authentication"
+// @Failure 403 {object} dto.ProblemResponse "include_deleted by a caller that is not an admin"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books [get]
-func GetBooks(c *gin.Context) {
+func (h *BookHandler) GetBooks(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
 	}
 
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
-		pageSize = 10
+	pageSize := parsePageSize(c)
+
+	filter, err := parseBookFilter(c)
+	if err != nil {
+		abortWithError(c, invalidQuery(err))
+		return
 	}
-	if pageSize > 100 {
-		pageSize = 100
+
+	filter.SubjectPath, err = h.subjectPath(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
 	}
 
-	books, totalCount, err := repository.GetAllBooks(page, pageSize)
+	filter.IncludeDeleted, err = parseIncludeDeleted(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
+		return
+	}
+
+	if cursorPagination(c) {
+		h.getBooksByCursor(c, filter, pageSize)
+		return
+	}
+
+	books, totalCount, err := h.books.GetAll(filter, page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
@@ -60,63 +129,330 @@ func GetBooks(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// getBooksByCursor serves GetBooks in cursor mode
+func (h *BookHandler) getBooksByCursor(c *gin.Context, filter repository.BookFilter, pageSize int) {
+	cursor, err := parseCursor(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	books, page, err := h.books.GetAllByCursor(filter, cursor, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	bookResponses := make([]dto.BookResponse, len(books))
+	for i, book := range books {
+		bookResponses[i] = dto.ToBookResponse(book)
+	}
+
+	c.JSON(http.StatusOK, dto.CursorBooksResponse{
+		Data:       bookResponses,
+		NextCursor: encodeCursor(page.Next),
+		PrevCursor: encodeCursor(page.Prev),
+		PageSize:   pageSize,
+	})
+}
+
+// parseBookFilter reads the filter and sort query parameters of GetBooks
+func parseBookFilter(c *gin.Context) (repository.BookFilter, error) {
+	var filter repository.BookFilter
+	var err error
+
+	if value := c.Query("author_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			return filter, fmt.Errorf("invalid author_id %q", value)
+		}
+		authorID := uint(id)
+		filter.AuthorID = &authorID
+	}
+
+	if value := c.Query("year_gte"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid year_gte %q", value)
+		}
+		filter.YearGte = &year
+	}
+
+	if value := c.Query("year_lte"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid year_lte %q", value)
+		}
+		filter.YearLte = &year
+	}
+	if filter.YearGte != nil && filter.YearLte != nil && *filter.YearGte > *filter.YearLte {
+		return filter, fmt.Errorf("year_gte must not be greater than year_lte")
+	}
+
+	filter.Title = strings.TrimSpace(c.Query("title"))
+
+	if value := c.Query("min_rating"); value != "" {
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil || rating < 1 || rating > 5 {
+			return filter, fmt.Errorf("invalid min_rating %q, expected a number between 1 and 5", value)
+		}
+		filter.MinRating = &rating
+	}
+
+	if filter.GenreIDs, filter.AllGenres, err = parseIDList(c, "genre"); err != nil {
+		return filter, err
+	}
+	if filter.TagIDs, filter.AllTags, err = parseIDList(c, "tag"); err != nil {
+		return filter, err
+	}
+
+	sort, err := repository.ParseSort(c.Query("sort"), repository.BookSortFields)
+	if err != nil {
+		return filter, err
+	}
+	filter.Sort = sort
+
+	return filter, nil
+}
+
+// subjectPath resolves the subject parameter of GetBooks to the path that the
+// subject and its descendants start with, "" when it is not set
+func (h *BookHandler) subjectPath(c *gin.Context) (string, error) {
+	value := c.Query("subject")
+	if value == "" {
+		return "", nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return "", invalidQuery(fmt.Errorf("invalid subject %q", value))
+	}
+
+	subject, err := h.subjects.GetByID(uint(id))
+	if err != nil {
+		return "", replaceNotFound(err, apperr.Validation("invalid_query", fmt.Sprintf("unknown subject %d", id)))
+	}
+	return subject.Path, nil
+}
+
+// parseIDList reads a comma separated list of IDs from the name parameter and
+// whether name_match asks for books matching all of them rather than any
+func parseIDList(c *gin.Context, name string) ([]uint, bool, error) {
+	var ids []uint
+	if value := c.Query(name); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil || id == 0 {
+				return nil, false, fmt.Errorf("invalid %s %q, expected comma separated IDs", name, value)
+			}
+			// Matching all of the IDs counts them, so each is only kept once
+			if !slices.Contains(ids, uint(id)) {
+				ids = append(ids, uint(id))
+			}
+		}
+	}
+
+	switch match := c.Query(name + "_match"); match {
+	case "", "any":
+		return ids, false, nil
+	case "all":
+		return ids, true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid %s_match %q, expected any or all", name, match)
+	}
+}
+
 // GetBook godoc
 // @Summary Get book by ID
-// @Description Get a book's details by ID with author and reviews
+// @Description Get a book's details by ID with its contributors, in credit order, reviews, editions, place in its series, linking the books before and after it, and the number of its copies by status
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} dto.BookDetailResponse
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 404 {object} map[string]string "Book not found"
+// @Header 200 {string} ETag "Changes with the book itself, not with its reviews or copies"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books/{id} [get]
-func GetBook(c *gin.Context) {
+func (h *BookHandler) GetBook(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	book, err := h.books.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, bookETag(*book)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBookDetailResponse(*book))
+}
+
+// GetBookByISBN godoc
+// @Summary Get book by ISBN
+// @Description Get a book's details by the ISBN-10 or ISBN-13 of any of its editions in any hyphenation, as read by a barcode scanner
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param isbn path string true "ISBN-10 or ISBN-13" example(978-0-7475-3269-9)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.BookDetailResponse
+// @Header 200 {string} ETag "Changes with the book itself, not with its reviews or copies"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ISBN"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(c *gin.Context) {
+	isbn13, err := isbn.Normalize(c.Param("isbn"))
+	if err != nil {
+		abortWithError(c, errInvalidISBN)
 		return
 	}
 
-	book, err := repository.GetBookByID(uint(id))
+	book, err := h.books.GetByISBN(isbn13)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, bookETag(*book)) {
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.ToBookDetailResponse(*book))
 }
 
+// LookupISBNs godoc
+// @Summary Look up books by ISBN in bulk
+// @Description Resolve up to 100 scanned ISBN-10 or ISBN-13 codes in one call. Every code gets a result in request order, and codes that are not in the catalog or are not valid ISBNs are also listed under unknown and invalid. Although a POST, it is a read and needs no authentication, like the single lookup.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param codes body dto.ISBNLookupRequest true "Scanned codes"
+// @Success 200 {object} dto.ISBNLookupResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid request body"
+// @Failure 403 {object} dto.ProblemResponse "API key is missing the books:read scope"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/isbn/lookup [post]
+func (h *BookHandler) LookupISBNs(c *gin.Context) {
+	var req dto.ISBNLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Normalize every code, then fetch all valid ones in a single query
+	results := make([]dto.ISBNLookupResult, len(req.ISBNs))
+	isbn13s := make([]string, 0, len(req.ISBNs))
+	for i, code := range req.ISBNs {
+		results[i].Query = code
+		isbn13, err := isbn.Normalize(code)
+		if err != nil {
+			results[i].Status = dto.ISBNLookupInvalid
+			continue
+		}
+		results[i].ISBN13 = isbn13
+		isbn13s = append(isbn13s, isbn13)
+	}
+
+	books, err := h.books.GetByISBNs(isbn13s)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Any edition of a book finds it
+	byISBN := make(map[string]dto.BookDetailResponse, len(books))
+	for _, book := range books {
+		detail := dto.ToBookDetailResponse(book)
+		for _, edition := range book.Editions {
+			if edition.ISBN13 != nil {
+				byISBN[*edition.ISBN13] = detail
+			}
+		}
+	}
+
+	response := dto.ISBNLookupResponse{Results: results, Unknown: []string{}, Invalid: []string{}}
+	for i := range results {
+		result := &results[i]
+		if result.Status == dto.ISBNLookupInvalid {
+			response.Invalid = append(response.Invalid, result.Query)
+			continue
+		}
+
+		book, ok := byISBN[result.ISBN13]
+		if !ok {
+			result.Status = dto.ISBNLookupUnknown
+			response.Unknown = append(response.Unknown, result.Query)
+			continue
+		}
+		result.Status = dto.ISBNLookupFound
+		result.Book = &book
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // CreateBook godoc
 // @Summary Create new book
-// @Description Add a new book to the library
+// @Description Add a new book to the library. The ISBN may be an ISBN-10 or ISBN-13, with or without hyphens, and must be unique. It becomes the ISBN of the book's first edition, add more editions with POST /api/v1/books/{id}/editions. Credit several authors with contributors, each with a role of author, editor, translator or illustrator, in credit order; the first author is the book's author_id. Put it in a series with series_id and a series_position, which may be fractional such as 2.5 for a novella. Requires the librarian or admin role.
 // @Tags books
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param book body dto.CreateBookRequest true "Book object that needs to be added"
 // @Success 201 {object} dto.BookResponse
-// @Failure 400 {object} map[string]string "Invalid input or author does not exist"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid input or author does not exist"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 409 {object} dto.ProblemResponse "A book with this ISBN already exists or another book has its position in the series"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books [post]
-func CreateBook(c *gin.Context) {
+func (h *BookHandler) CreateBook(c *gin.Context) {
 	var req dto.CreateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(c, bindingError(err))
 		return
 	}
 
-	// Check if author exists
-	_, err := repository.GetAuthorByID(req.AuthorID)
+	// Check that the credited authors exist
+	authors, err := h.contributorAuthors(req.AuthorID, req.Contributors)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Author does not exist"})
+		abortWithError(c, err)
 		return
 	}
 
 	// Convert DTO to model
 	book := dto.CreateBookRequestToModel(req)
+	attachAuthors(&book, authors)
+
+	// Check that the genres, tags and subjects exist
+	if err := h.classify(&book); err != nil {
+		abortWithError(c, err)
+		return
+	}
 
-	if err := repository.CreateBook(&book); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Check that the series exists and the position is free
+	if err := h.placeInSeries(&book); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.books.Create(&book); err != nil {
+		// Another book may have taken the ISBN or the position in the
+		// series since they were checked
+		abortWithError(c, replaceBookConflict(err))
 		return
 	}
 
@@ -124,79 +460,388 @@ func CreateBook(c *gin.Context) {
 }
 
 // UpdateBook godoc
-// @Summary Update book
-// @Description Update an existing book
+// @Summary Replace book
+// @Description Replace an existing book. Optional fields that are left out are cleared, and contributors replaces the book's credits. Requires the librarian or admin role.
 // @Tags books
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Book ID" minimum(1)
-// @Param book body dto.UpdateBookRequest true "Book object that needs to be updated"
+// @Param If-Match header string false "ETag of the book the change is based on"
+// @Param book body dto.UpdateBookRequest true "The new state of the book"
 // @Success 200 {object} dto.BookResponse
-// @Failure 400 {object} map[string]string "Invalid ID format or request body"
-// @Failure 404 {object} map[string]string "Book not found"
-// @Failure 500 {object} map[string]string "Server error"
+// @Header 200 {string} ETag "ETag of the updated book"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 409 {object} dto.ProblemResponse "A book with this ISBN already exists, another book has its position in the series or the book was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The book has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books/{id} [put]
-func UpdateBook(c *gin.Context) {
+func (h *BookHandler) UpdateBook(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	book, err := repository.GetBookByID(uint(id))
+	book, err := h.books.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, bookETag(*book)); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	var req dto.UpdateBookRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveBook(c, book, req)
+}
+
+// PatchBook godoc
+// @Summary Update book
+// @Description Change some fields of a book with a JSON Merge Patch, where null clears a field, or a JSON Patch. A book with a single author is patched through author_id and other books through contributors, which is replaced as a whole by a merge patch. Requires the librarian or admin role.
+// @Tags books
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param If-Match header string false "ETag of the book the change is based on"
+// @Param book body dto.UpdateBookRequest true "The fields to change"
+// @Success 200 {object} dto.BookResponse
+// @Header 200 {string} ETag "ETag of the updated book"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting book"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 409 {object} dto.ProblemResponse "A book with this ISBN already exists, another book has its position in the series, a test operation failed or the book was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The book has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id} [patch]
+func (h *BookHandler) PatchBook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	book, err := h.books.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, bookETag(*book)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateBookRequest
+	if err := bindPatch(c, dto.ToUpdateBookRequest(*book), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveBook(c, book, req)
+}
+
+// saveBook replaces book with req and responds with the result
+func (h *BookHandler) saveBook(c *gin.Context, book *models.Book, req dto.UpdateBookRequest) {
+	// Check that the credited authors exist
+	authors, err := h.contributorAuthors(req.AuthorID, req.Contributors)
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	// Update model from DTO
 	dto.UpdateBookModelFromRequest(book, req)
+	// Keep the loaded authors in step with the request
+	attachAuthors(book, authors)
 
-	// Check if author exists if author_id has changed
-	if req.AuthorID != 0 {
-		_, err := repository.GetAuthorByID(req.AuthorID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Author does not exist"})
-			return
-		}
+	// Check that the genres, tags and subjects exist
+	if err := h.classify(book); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Check that the series exists and the position is free
+	if err := h.placeInSeries(book); err != nil {
+		abortWithError(c, err)
+		return
 	}
 
-	if err := repository.UpdateBook(book); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.books.Update(book); err != nil {
+		abortWithError(c, replaceBookConflict(err))
 		return
 	}
 
+	c.Header("ETag", bookETag(*book))
 	c.JSON(http.StatusOK, dto.ToBookResponse(*book))
 }
 
+// contributorAuthors checks the author_id and contributors of a book request
+// and loads the credited authors by ID
+func (h *BookHandler) contributorAuthors(authorID uint, contributors []dto.ContributorRequest) (map[uint]models.Author, error) {
+	if len(contributors) == 0 {
+		author, err := h.authors.GetByID(authorID)
+		if err != nil {
+			return nil, replaceNotFound(err, errAuthorDoesNotExist)
+		}
+		return map[uint]models.Author{authorID: *author}, nil
+	}
+
+	primary := dto.PrimaryAuthorID(contributors)
+	if primary == 0 {
+		return nil, invalidContributors(apperr.FieldError{Field: "contributors", Rule: "primary_author", Message: "must include an author in the author role"})
+	}
+	if authorID != 0 && authorID != primary {
+		return nil, invalidContributors(apperr.FieldError{Field: "author_id", Rule: "primary_author", Message: "must be the first contributor in the author role, or be left out"})
+	}
+
+	seen := make(map[dto.ContributorRequest]bool, len(contributors))
+	authors := make(map[uint]models.Author, len(contributors))
+	for i, contributor := range contributors {
+		if seen[contributor] {
+			return nil, invalidContributors(apperr.FieldError{Field: fmt.Sprintf("contributors[%d]", i), Rule: "unique", Message: "credits the same author in the same role twice"})
+		}
+		seen[contributor] = true
+
+		if _, ok := authors[contributor.AuthorID]; ok {
+			continue
+		}
+		author, err := h.authors.GetByID(contributor.AuthorID)
+		if err != nil {
+			field := apperr.FieldError{Field: fmt.Sprintf("contributors[%d].author_id", i), Rule: "exists", Message: "is not an existing author"}
+			return nil, replaceNotFound(err, apperr.Validation(errAuthorDoesNotExist.Code, errAuthorDoesNotExist.Message, field))
+		}
+		authors[contributor.AuthorID] = *author
+	}
+	return authors, nil
+}
+
+// classify replaces the genres, tags and subjects of a book, which only have
+// their IDs as sent in the request, with the loaded records, failing with the
+// first one that does not exist
+func (h *BookHandler) classify(book *models.Book) error {
+	genres, err := h.genres.GetByIDs(ids(book.Genres, func(genre models.Genre) uint { return genre.ID }))
+	if err != nil {
+		return err
+	}
+	for i, requested := range book.Genres {
+		if !slices.ContainsFunc(genres, func(genre models.Genre) bool { return genre.ID == requested.ID }) {
+			field := apperr.FieldError{Field: fmt.Sprintf("genres[%d]", i), Rule: "exists", Message: "is not an existing genre"}
+			return apperr.Validation(errGenreDoesNotExist.Code, errGenreDoesNotExist.Message, field)
+		}
+	}
+
+	tags, err := h.tags.GetByIDs(ids(book.Tags, func(tag models.Tag) uint { return tag.ID }))
+	if err != nil {
+		return err
+	}
+	for i, requested := range book.Tags {
+		if !slices.ContainsFunc(tags, func(tag models.Tag) bool { return tag.ID == requested.ID }) {
+			field := apperr.FieldError{Field: fmt.Sprintf("tags[%d]", i), Rule: "exists", Message: "is not an existing tag"}
+			return apperr.Validation(errTagDoesNotExist.Code, errTagDoesNotExist.Message, field)
+		}
+	}
+
+	subjects, err := h.subjects.GetByIDs(ids(book.Subjects, func(subject models.Subject) uint { return subject.ID }))
+	if err != nil {
+		return err
+	}
+	for i, requested := range book.Subjects {
+		if !slices.ContainsFunc(subjects, func(subject models.Subject) bool { return subject.ID == requested.ID }) {
+			field := apperr.FieldError{Field: fmt.Sprintf("subjects[%d]", i), Rule: "exists", Message: "is not an existing subject"}
+			return apperr.Validation(errSubjectDoesNotExist.Code, errSubjectDoesNotExist.Message, field)
+		}
+	}
+
+	book.Genres, book.Tags, book.Subjects = genres, tags, subjects
+	return nil
+}
+
+// placeInSeries loads the series of a book, which only has its ID as sent in
+// the request, and checks that no other book in the series has its position
+func (h *BookHandler) placeInSeries(book *models.Book) error {
+	book.Series = nil
+	if book.SeriesID == nil {
+		return nil
+	}
+
+	series, err := h.series.GetByID(*book.SeriesID)
+	if err != nil {
+		return replaceNotFound(err, errSeriesDoesNotExist)
+	}
+
+	books, err := h.series.GetBooks(series.ID)
+	if err != nil {
+		return err
+	}
+	if book.SeriesPosition != nil && slices.ContainsFunc(books, func(other models.Book) bool {
+		return other.ID != book.ID && other.SeriesPosition != nil && *other.SeriesPosition == *book.SeriesPosition
+	}) {
+		return errSeriesPositionTaken
+	}
+
+	book.Series = series
+	return nil
+}
+
+// replaceBookConflict reports the unique constraints on books, the ISBN and
+// the position in the series, in terms of the request
+func replaceBookConflict(err error) error {
+	switch apperr.From(err).Code {
+	case "book_exists":
+		return errISBNExists
+	case "book_position_taken":
+		return errSeriesPositionTaken
+	}
+	return err
+}
+
+// ids returns the ID of each record
+func ids[T any](records []T, id func(T) uint) []uint {
+	result := make([]uint, len(records))
+	for i, record := range records {
+		result[i] = id(record)
+	}
+	return result
+}
+
+// invalidContributors reports a contributors list that breaks a rule the binding cannot express
+func invalidContributors(field apperr.FieldError) error {
+	return apperr.Validation("validation_failed", "Request body failed validation", field)
+}
+
+// attachAuthors sets the loaded authors of a book and its contributors
+func attachAuthors(book *models.Book, authors map[uint]models.Author) {
+	book.Author = authors[book.AuthorID]
+	for i := range book.Contributors {
+		book.Contributors[i].Author = authors[book.Contributors[i].AuthorID]
+	}
+}
+
 // DeleteBook godoc
 // @Summary Delete book
-// @Description Delete a book
+// @Description Delete a book together with its reviews, editions and copies. Requires the librarian or admin role.
 // @Tags books
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Book ID" minimum(1)
+// @Param If-Match header string false "ETag of the book the deletion is based on"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found, for conditional deletes"
+// @Failure 412 {object} dto.ProblemResponse "The book has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books/{id} [delete]
-func DeleteBook(c *gin.Context) {
+func (h *BookHandler) DeleteBook(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	if err := repository.DeleteBook(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Only conditional deletes need the current book
+	if c.GetHeader("If-Match") != "" {
+		book, err := h.books.GetByID(uint(id))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, bookETag(*book)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.books.Delete(uint(id)); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreBook godoc
+// @Summary Restore deleted book
+// @Description Undo the deletion of a book, together with the reviews, editions and copies that were deleted with it. Reviews deleted before the book stay deleted. Requires the librarian or admin role.
+// @Tags books
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Success 200 {object} dto.BookResponse
+// @Header 200 {string} ETag "ETag of the restored book"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 409 {object} dto.ProblemResponse "The book is not deleted, its author is deleted or another book now has its ISBN or the barcode of one of its copies"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/restore [post]
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	deleted, err := h.books.GetDeletedByID(uint(id))
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		// Tell a live book apart from one that does not exist
+		if _, liveErr := h.books.GetByID(uint(id)); liveErr == nil {
+			err = errBookNotDeleted
+		}
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// A restored book must not point at a hidden author
+	if _, err := h.authors.GetByID(deleted.AuthorID); err != nil {
+		abortWithError(c, replaceNotFound(err, errAuthorDeleted))
+		return
+	}
+
+	if err := h.books.Restore(uint(id)); err != nil {
+		// The ISBN, the position in the series or the barcode of a copy may
+		// have been given to another book in the meantime
+		err = replaceBookConflict(err)
+		if apperr.From(err).Code == "copy_exists" {
+			err = errBarcodeExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	book, err := h.books.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", bookETag(*book))
+	c.JSON(http.StatusOK, dto.ToBookResponse(*book))
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Copy errors reported in terms of the request
+var (
+	errBarcodeExists = apperr.Conflict("barcode_exists", "A copy with this barcode already exists")
+	errCopyNotFound  = apperr.NotFound("copy_not_found", "Copy not found")
+)
+
+// copyStatuses are the values of the status filter of GetBookCopies
+var copyStatuses = []string{models.CopyAvailable, models.CopyOnLoan, models.CopyLost, models.CopyRepair}
+
+// CopyHandler serves the endpoints for the physical copies of books
+type CopyHandler struct {
+	copies repository.CopyRepository
+	books  repository.BookRepository
+}
+
+// NewCopyHandler creates a CopyHandler backed by the given repositories
+func NewCopyHandler(copies repository.CopyRepository, books repository.BookRepository) *CopyHandler {
+	return &CopyHandler{copies: copies, books: books}
+}
+
+// GetBookCopies godoc
+// @Summary Get copies of a book
+// @Description Get the physical copies of a book ordered by ID, optionally only those with a status
+// @Tags copies
+// @Accept json
+// @Produce json
+// @Param id path int true "Book ID" minimum(1)
+// @Param status query string false "Only copies with this status" Enums(available, on_loan, lost, repair)
+// @Param If-None-Match header string false "ETag of a cached list"
+// @Success 200 {array} dto.CopyResponse
+// @Header 200 {string} ETag "Changes with the copies of the book, and so with its availability"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or status"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies [get]
+func (h *CopyHandler) GetBookCopies(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && !slices.Contains(copyStatuses, status) {
+		abortWithError(c, apperr.Validation("invalid_query", "Invalid status, expected available, on_loan, lost or repair"))
+		return
+	}
+
+	// Verify that the book exists
+	if _, err := h.books.GetByID(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	copies, err := h.copies.GetByBookID(uint(id), status)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, copiesETag(copies)) {
+		return
+	}
+
+	// Convert models to DTOs
+	copyResponses := make([]dto.CopyResponse, len(copies))
+	for i, bookCopy := range copies {
+		copyResponses[i] = dto.ToCopyResponse(bookCopy)
+	}
+
+	c.JSON(http.StatusOK, copyResponses)
+}
+
+// GetCopy godoc
+// @Summary Get copy by ID
+// @Description Get a single copy of a book, with the ETag needed for conditional updates
+// @Tags copies
+// @Accept json
+// @Produce json
+// @Param id path int true "Book ID" minimum(1)
+// @Param copy_id path int true "Copy ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.CopyResponse
+// @Header 200 {string} ETag "Changes with the copy"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Copy not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies/{copy_id} [get]
+func (h *CopyHandler) GetCopy(c *gin.Context) {
+	bookCopy, err := h.loadCopy(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, copyETag(*bookCopy)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCopyResponse(*bookCopy))
+}
+
+// loadCopy loads the copy named by the copy_id parameter, which must be a copy
+// of the book named by the id parameter
+func (h *CopyHandler) loadCopy(c *gin.Context) (*models.Copy, error) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, errInvalidID
+	}
+	id, err := strconv.ParseUint(c.Param("copy_id"), 10, 32)
+	if err != nil {
+		return nil, errInvalidID
+	}
+
+	bookCopy, err := h.copies.GetByID(uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if bookCopy.BookID != uint(bookID) {
+		return nil, errCopyNotFound
+	}
+	return bookCopy, nil
+}
+
+// AddCopy godoc
+// @Summary Add copy to book
+// @Description Add a physical copy of a book, with a barcode that must be unique across all copies. New copies are available unless the request says otherwise. Requires the librarian or admin role.
+// @Tags copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param copy body dto.CreateCopyRequest true "Copy object that needs to be added"
+// @Success 201 {object} dto.CopyResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 409 {object} dto.ProblemResponse "A copy with this barcode already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies [post]
+func (h *CopyHandler) AddCopy(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Verify that the book exists
+	if _, err := h.books.GetByID(uint(bookID)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.CreateCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	bookCopy := dto.CreateCopyRequestToModel(uint(bookID), req)
+
+	if err := h.copies.Create(&bookCopy); err != nil {
+		if apperr.From(err).Code == "copy_exists" {
+			err = errBarcodeExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCopyResponse(bookCopy))
+}
+
+// UpdateCopy godoc
+// @Summary Replace copy
+// @Description Replace an existing copy, for example to record that it is on loan or being repaired. Optional fields that are left out are cleared. Requires the librarian or admin role.
+// @Tags copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param copy_id path int true "Copy ID" minimum(1)
+// @Param If-Match header string false "ETag of the copy the change is based on"
+// @Param copy body dto.UpdateCopyRequest true "The new state of the copy"
+// @Success 200 {object} dto.CopyResponse
+// @Header 200 {string} ETag "ETag of the updated copy"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Copy not found"
+// @Failure 409 {object} dto.ProblemResponse "A copy with this barcode already exists or the copy was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The copy has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies/{copy_id} [put]
+func (h *CopyHandler) UpdateCopy(c *gin.Context) {
+	bookCopy, err := h.loadCopy(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, copyETag(*bookCopy)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveCopy(c, bookCopy, req)
+}
+
+// PatchCopy godoc
+// @Summary Update copy
+// @Description Change some fields of a copy with a JSON Merge Patch, where null clears a field, or a JSON Patch. Requires the librarian or admin role.
+// @Tags copies
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param copy_id path int true "Copy ID" minimum(1)
+// @Param If-Match header string false "ETag of the copy the change is based on"
+// @Param copy body dto.UpdateCopyRequest true "The fields to change"
+// @Success 200 {object} dto.CopyResponse
+// @Header 200 {string} ETag "ETag of the updated copy"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting copy"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Copy not found"
+// @Failure 409 {object} dto.ProblemResponse "A copy with this barcode already exists, a test operation failed or the copy was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The copy has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies/{copy_id} [patch]
+func (h *CopyHandler) PatchCopy(c *gin.Context) {
+	bookCopy, err := h.loadCopy(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, copyETag(*bookCopy)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateCopyRequest
+	if err := bindPatch(c, dto.ToUpdateCopyRequest(*bookCopy), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveCopy(c, bookCopy, req)
+}
+
+// saveCopy replaces bookCopy with req and responds with the result
+func (h *CopyHandler) saveCopy(c *gin.Context, bookCopy *models.Copy, req dto.UpdateCopyRequest) {
+	// Update model from DTO
+	dto.UpdateCopyModelFromRequest(bookCopy, req)
+
+	if err := h.copies.Update(bookCopy); err != nil {
+		if apperr.From(err).Code == "copy_exists" {
+			err = errBarcodeExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", copyETag(*bookCopy))
+	c.JSON(http.StatusOK, dto.ToCopyResponse(*bookCopy))
+}
+
+// DeleteCopy godoc
+// @Summary Delete copy
+// @Description Delete a copy that has left the library for good. Copies are not soft deleted on their own and cannot be restored, a lost copy can be kept with the lost status instead. Requires the librarian or admin role.
+// @Tags copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param copy_id path int true "Copy ID" minimum(1)
+// @Param If-Match header string false "ETag of the copy the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Copy not found, for conditional deletes"
+// @Failure 412 {object} dto.ProblemResponse "The copy has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/copies/{copy_id} [delete]
+func (h *CopyHandler) DeleteCopy(c *gin.Context) {
+	// The copy is loaded to check that it belongs to the book
+	bookCopy, err := h.loadCopy(c)
+	if err != nil {
+		if apperr.KindOf(err) == apperr.KindNotFound && c.GetHeader("If-Match") == "" {
+			// Deleting a copy that is already gone succeeds, like other deletes
+			c.Status(http.StatusNoContent)
+			return
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, copyETag(*bookCopy)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.copies.Delete(bookCopy.ID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Edition errors reported in terms of the request
+var (
+	errPublisherDoesNotExist = apperr.Validation("publisher_not_found", "Publisher does not exist",
+		apperr.FieldError{Field: "publisher_id", Rule: "exists", Message: "is not an existing publisher"})
+	errFirstEdition = apperr.Conflict("first_edition",
+		"The first edition has the book's ISBN and is only deleted with the book")
+)
+
+// EditionHandler serves the edition endpoints
+type EditionHandler struct {
+	editions   repository.EditionRepository
+	books      repository.BookRepository
+	publishers repository.PublisherRepository
+}
+
+// NewEditionHandler creates an EditionHandler backed by the given repositories
+func NewEditionHandler(editions repository.EditionRepository, books repository.BookRepository, publishers repository.PublisherRepository) *EditionHandler {
+	return &EditionHandler{editions: editions, books: books, publishers: publishers}
+}
+
+// GetBookEditions godoc
+// @Summary Get editions of a book
+// @Description Get all editions of a book in the order they were added, starting with the first edition, which has the book's ISBN
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Param id path int true "Book ID" minimum(1)
+// @Success 200 {array} dto.EditionResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/editions [get]
+func (h *EditionHandler) GetBookEditions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Verify that the book exists
+	if _, err := h.books.GetByID(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	editions, err := h.editions.GetByBookID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	editionResponses := make([]dto.EditionResponse, len(editions))
+	for i, edition := range editions {
+		editionResponses[i] = dto.ToEditionResponse(edition)
+	}
+
+	c.JSON(http.StatusOK, editionResponses)
+}
+
+// GetEdition godoc
+// @Summary Get edition by ID
+// @Description Get a single edition with its publisher, with the ETag needed for conditional updates
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Param id path int true "Edition ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.EditionResponse
+// @Header 200 {string} ETag "Changes with the edition and its publisher"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Edition not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/editions/{id} [get]
+func (h *EditionHandler) GetEdition(c *gin.Context) {
+	edition, err := h.loadEdition(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, editionETag(*edition)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToEditionResponse(*edition))
+}
+
+// loadEdition loads the edition named by the id parameter
+func (h *EditionHandler) loadEdition(c *gin.Context) (*models.Edition, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, errInvalidID
+	}
+	return h.editions.GetByID(uint(id))
+}
+
+// AddEdition godoc
+// @Summary Add edition to book
+// @Description Add another edition of a book, such as a paperback or a translation, with its own ISBN, which must be unique across all editions. Requires the librarian or admin role.
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Book ID" minimum(1)
+// @Param edition body dto.CreateEditionRequest true "Edition object that needs to be added"
+// @Success 201 {object} dto.EditionResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body, or publisher does not exist"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 409 {object} dto.ProblemResponse "A book or edition with this ISBN already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/books/{id}/editions [post]
+func (h *EditionHandler) AddEdition(c *gin.Context) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Verify that the book exists
+	if _, err := h.books.GetByID(uint(bookID)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.CreateEditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	edition := dto.CreateEditionRequestToModel(uint(bookID), req)
+
+	if err := h.loadPublisher(&edition); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.editions.Create(&edition); err != nil {
+		if apperr.From(err).Code == "edition_exists" {
+			err = errISBNExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToEditionResponse(edition))
+}
+
+// UpdateEdition godoc
+// @Summary Replace edition
+// @Description Replace an existing edition. Optional fields that are left out are cleared. Changing the ISBN of a book's first edition changes the ISBN of the book. Requires the librarian or admin role.
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Edition ID" minimum(1)
+// @Param If-Match header string false "ETag of the edition the change is based on"
+// @Param edition body dto.UpdateEditionRequest true "The new state of the edition"
+// @Success 200 {object} dto.EditionResponse
+// @Header 200 {string} ETag "ETag of the updated edition"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body, or publisher does not exist"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Edition not found"
+// @Failure 409 {object} dto.ProblemResponse "A book or edition with this ISBN already exists or the edition was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The edition has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/editions/{id} [put]
+func (h *EditionHandler) UpdateEdition(c *gin.Context) {
+	edition, err := h.loadEdition(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, editionETag(*edition)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateEditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveEdition(c, edition, req)
+}
+
+// PatchEdition godoc
+// @Summary Update edition
+// @Description Change some fields of an edition with a JSON Merge Patch, where null clears a field, or a JSON Patch. Changing the ISBN of a book's first edition changes the ISBN of the book. Requires the librarian or admin role.
+// @Tags editions
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Edition ID" minimum(1)
+// @Param If-Match header string false "ETag of the edition the change is based on"
+// @Param edition body dto.UpdateEditionRequest true "The fields to change"
+// @Success 200 {object} dto.EditionResponse
+// @Header 200 {string} ETag "ETag of the updated edition"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting edition"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Edition not found"
+// @Failure 409 {object} dto.ProblemResponse "A book or edition with this ISBN already exists, a test operation failed or the edition was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The edition has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/editions/{id} [patch]
+func (h *EditionHandler) PatchEdition(c *gin.Context) {
+	edition, err := h.loadEdition(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, editionETag(*edition)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateEditionRequest
+	if err := bindPatch(c, dto.ToUpdateEditionRequest(*edition), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveEdition(c, edition, req)
+}
+
+// saveEdition replaces edition with req and responds with the result
+func (h *EditionHandler) saveEdition(c *gin.Context, edition *models.Edition, req dto.UpdateEditionRequest) {
+	// Update model from DTO
+	dto.UpdateEditionModelFromRequest(edition, req)
+
+	if err := h.loadPublisher(edition); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.editions.Update(edition); err != nil {
+		if apperr.From(err).Code == "edition_exists" {
+			err = errISBNExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", editionETag(*edition))
+	c.JSON(http.StatusOK, dto.ToEditionResponse(*edition))
+}
+
+// loadPublisher loads the publisher of an edition, which only has its ID as
+// sent in the request
+func (h *EditionHandler) loadPublisher(edition *models.Edition) error {
+	edition.Publisher = nil
+	if edition.PublisherID == nil {
+		return nil
+	}
+
+	publisher, err := h.publishers.GetByID(*edition.PublisherID)
+	if err != nil {
+		return replaceNotFound(err, errPublisherDoesNotExist)
+	}
+	edition.Publisher = publisher
+	return nil
+}
+
+// DeleteEdition godoc
+// @Summary Delete edition
+// @Description Delete an edition other than the first edition of its book, which has the book's ISBN and is only deleted with the book. Editions are not soft deleted and cannot be restored on their own. Requires the librarian or admin role.
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Edition ID" minimum(1)
+// @Param If-Match header string false "ETag of the edition the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Edition not found, for conditional deletes"
+// @Failure 409 {object} dto.ProblemResponse "The edition is the first edition of its book"
+// @Failure 412 {object} dto.ProblemResponse "The edition has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/editions/{id} [delete]
+func (h *EditionHandler) DeleteEdition(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current edition
+	if c.GetHeader("If-Match") != "" {
+		edition, err := h.loadEdition(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, editionETag(*edition)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.editions.Delete(uint(id)); err != nil {
+		if apperr.From(err).Code == "edition_in_use" {
+			err = errFirstEdition
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
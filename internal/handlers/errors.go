@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/isbn"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// errInvalidID is returned for path IDs that are not positive integers
+var errInvalidID = apperr.Validation("invalid_id", "Invalid ID format")
+
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Report validation failures by JSON field name rather than Go field name
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// Replace the validator's own isbn rule, which rejects hyphenated ISBN-13s,
+	// with the checksum validation used to normalize ISBNs for storage
+	_ = engine.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		return isbn.Valid(fl.Field().String())
+	})
+}
+
+// abortWithError stops the handler chain and leaves err for the ErrorHandler middleware to render
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// invalidQuery wraps a query parameter parsing error as a validation error
+func invalidQuery(err error) error {
+	return apperr.Validation("invalid_query", err.Error())
+}
+
+// replaceNotFound returns replacement when err is a not found error and err otherwise,
+// for lookups whose failure means something else to the caller
+func replaceNotFound(err error, replacement *apperr.Error) error {
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		return replacement
+	}
+	return err
+}
+
+// bindingError converts a ShouldBindJSON failure to a validation error listing
+// the invalid fields by their JSON names, so clients can point at the exact input
+func bindingError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]apperr.FieldError, len(validationErrors))
+		for i, fieldErr := range validationErrors {
+			fields[i] = apperr.FieldError{
+				Field:   fieldPath(fieldErr),
+				Rule:    fieldErr.Tag(),
+				Message: validationMessage(fieldErr),
+			}
+		}
+		return apperr.Validation("validation_failed", "Request body failed validation", fields...)
+	}
+
+	// A value of the wrong JSON type, such as "rating": "five"
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field := apperr.FieldError{Field: typeErr.Field, Rule: "type", Message: "must be " + jsonTypeName(typeErr.Type)}
+		return apperr.Validation("validation_failed", "Request body failed validation", field)
+	}
+
+	return apperr.Validation("invalid_body", "Malformed request body: "+err.Error())
+}
+
+// fieldPath returns the JSON path of a failed field, e.g. scopes[1], without
+// the name of the request struct that the validator puts first
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// validationMessage describes a failed validation rule in words
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is left out", snakeCase(fieldErr.Param()))
+	case "required_with":
+		return fmt.Sprintf("is required with %s", snakeCase(fieldErr.Param()))
+	case "excluded_without":
+		return fmt.Sprintf("must be left out without %s", snakeCase(fieldErr.Param()))
+	case "email":
+		return "must be a valid email address"
+	case "isbn":
+		return "must be a valid ISBN-10 or ISBN-13"
+	case "bcp47_language_tag":
+		return "must be a BCP 47 language tag such as en or pt-BR"
+	case "datetime":
+		return fmt.Sprintf("must be a date like %s", fieldErr.Param())
+	case "min", "max", "gte", "lte":
+		bound := "at least"
+		if fieldErr.Tag() == "max" || fieldErr.Tag() == "lte" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fieldErr.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must contain %s %s items", bound, fieldErr.Param())
+		default:
+			return fmt.Sprintf("must be %s %s", bound, fieldErr.Param())
+		}
+	case "unique":
+		return "must not contain duplicates"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
+// snakeCase converts the Go name of a field that a rule refers to, such as
+// SeriesID, to its JSON name
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(name[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// jsonTypeName describes the JSON value expected for a Go type
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
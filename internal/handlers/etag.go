@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/models"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errETagMismatch = apperr.PreconditionFailed("etag_mismatch",
+	"The resource has changed since it was read, fetch it again and retry")
+
+// versionedRef identifies one version of a resource embedded in a representation
+type versionedRef struct {
+	kind        string
+	id, version uint
+}
+
+// entityTag formats a strong ETag from the version of a resource and of the
+// resources embedded in its representation, so that changing either changes it
+func entityTag(version uint, embedded ...versionedRef) string {
+	if len(embedded) == 0 {
+		return fmt.Sprintf(`"%d"`, version)
+	}
+
+	// Sorted so that the tag does not depend on the order rows were loaded in
+	slices.SortFunc(embedded, func(a, b versionedRef) int {
+		return cmp.Or(cmp.Compare(a.kind, b.kind), cmp.Compare(a.id, b.id))
+	})
+	hash := fnv.New64a()
+	for _, ref := range embedded {
+		fmt.Fprintf(hash, "%s/%d:%d,", ref.kind, ref.id, ref.version)
+	}
+	return fmt.Sprintf(`"%d-%x"`, version, hash.Sum64())
+}
+
+// bookETag is the version of the book itself. What is embedded in it, above
+// all its reviews and the availability of its copies, changes without the
+// book, and must not fail an If-Match of a change to the book. Its reviews and
+// copies are versioned by reviewsETag and copiesETag instead.
+func bookETag(book models.Book) string {
+	return entityTag(book.Version)
+}
+
+// reviewsETag covers the reviews of a book, as listed by GetBookReviews
+func reviewsETag(reviews []models.Review) string {
+	embedded := make([]versionedRef, len(reviews))
+	for i, review := range reviews {
+		// Deleting a review does not bump its version
+		kind := "review"
+		if review.DeletedAt.Valid {
+			kind = "deleted_review"
+		}
+		embedded[i] = versionedRef{kind, review.ID, review.Version}
+	}
+	return entityTag(uint(len(reviews)), embedded...)
+}
+
+// copiesETag covers the copies of a book, and so its availability, as listed by GetBookCopies
+func copiesETag(copies []models.Copy) string {
+	embedded := make([]versionedRef, len(copies))
+	for i, bookCopy := range copies {
+		embedded[i] = versionedRef{"copy", bookCopy.ID, bookCopy.Version}
+	}
+	return entityTag(uint(len(copies)), embedded...)
+}
+
+// authorETag covers the author and the books it is credited on, as returned by GetAuthor
+func authorETag(author models.Author) string {
+	embedded := make([]versionedRef, 0, len(author.Books)+len(author.Contributions))
+	for _, book := range author.Books {
+		embedded = append(embedded, versionedRef{"book", book.ID, book.Version})
+	}
+	for _, contribution := range author.Contributions {
+		embedded = append(embedded, versionedRef{"book", contribution.Book.ID, contribution.Book.Version})
+	}
+	return entityTag(author.Version, embedded...)
+}
+
+func reviewETag(review models.Review) string {
+	return entityTag(review.Version)
+}
+
+// editionETag covers the edition and its publisher, as returned by GetEdition
+func editionETag(edition models.Edition) string {
+	return entityTag(edition.Version, editionRefs(edition)[1:]...)
+}
+
+// editionRefs lists the versions of an edition and its publisher
+func editionRefs(edition models.Edition) []versionedRef {
+	refs := []versionedRef{{"edition", edition.ID, edition.Version}}
+	if edition.Publisher != nil {
+		refs = append(refs, versionedRef{"publisher", edition.Publisher.ID, edition.Publisher.Version})
+	}
+	return refs
+}
+
+func copyETag(bookCopy models.Copy) string {
+	return entityTag(bookCopy.Version)
+}
+
+// publisherETag covers the publisher and its number of editions, as returned by GetPublisher
+func publisherETag(publisher models.Publisher, editionCount int64) string {
+	return entityTag(publisher.Version, versionedRef{"editions", 0, uint(editionCount)})
+}
+
+// genreETag covers the genre and its number of books, as returned by GetGenre
+func genreETag(genre models.Genre, bookCount int64) string {
+	return entityTag(genre.Version, versionedRef{"books", 0, uint(bookCount)})
+}
+
+// tagETag covers the tag and its number of books, as returned by GetTag
+func tagETag(tag models.Tag, bookCount int64) string {
+	return entityTag(tag.Version, versionedRef{"books", 0, uint(bookCount)})
+}
+
+// subjectETag covers the subject and its ancestors, as returned by GetSubject
+func subjectETag(subject models.Subject, ancestors []models.Subject) string {
+	embedded := make([]versionedRef, len(ancestors))
+	for i, ancestor := range ancestors {
+		embedded[i] = versionedRef{"subject", ancestor.ID, ancestor.Version}
+	}
+	return entityTag(subject.Version, embedded...)
+}
+
+// seriesETag covers the series and its books, as returned by GetSeries
+func seriesETag(series models.Series, books []models.Book) string {
+	embedded := make([]versionedRef, len(books))
+	for i, book := range books {
+		embedded[i] = versionedRef{"book", book.ID, book.Version}
+	}
+	return entityTag(series.Version, embedded...)
+}
+
+// notModified sets the ETag of a read and reports whether it matches
+// If-None-Match, in which case it has already responded with 304
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	// If-None-Match uses the weak comparison, which ignores W/
+	if strings.TrimSpace(header) != "*" && !slices.ContainsFunc(strings.Split(header, ","), func(tag string) bool {
+		return strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag
+	}) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// checkIfMatch fails with 412 when the request has an If-Match header that
+// does not list etag, the current ETag of the resource being changed
+func checkIfMatch(c *gin.Context, etag string) error {
+	header := c.GetHeader("If-Match")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return nil
+	}
+	// If-Match uses the strong comparison, so weak tags never match
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == etag {
+			return nil
+		}
+	}
+	return errETagMismatch
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"go-rest-api/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBookETagIgnoresReviewsAndCopies(t *testing.T) {
+	s := newTestServer(t)
+	librarian := s.user(t, models.RoleLibrarian)
+	authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+	bookID := s.created(t, "/api/v1/books", bookBody(authorID, "978-0-7475-3269-9"), "Authorization", librarian)
+	path := fmt.Sprintf("/api/v1/books/%d", bookID)
+	etag := s.do(t, http.MethodGet, path, nil).Header().Get("ETag")
+
+	// A review and a loan change what GetBook embeds, not the book
+	s.created(t, path+"/reviews", reviewBody(bookID, 5), "Authorization", s.user(t, models.RoleUser))
+	copyID := s.created(t, path+"/copies", gin.H{"barcode": "LIB-1"}, "Authorization", librarian)
+	w := s.do(t, http.MethodPut, fmt.Sprintf("%s/copies/%d", path, copyID), gin.H{"barcode": "LIB-1", "status": "on_loan"}, "Authorization", librarian)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT copy = %d: %s", w.Code, w.Body)
+	}
+	if got := s.do(t, http.MethodGet, path, nil).Header().Get("ETag"); got != etag {
+		t.Errorf("ETag after a review and a loan = %s, want %s", got, etag)
+	}
+
+	w = s.do(t, http.MethodPut, path, bookBody(authorID, "978-0-7475-3269-9"), "Authorization", librarian, "If-Match", etag)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT with the ETag read before the review = %d: %s", w.Code, w.Body)
+	}
+	if updated := w.Header().Get("ETag"); updated == etag {
+		t.Errorf("ETag after an update = %s, want a new one", updated)
+	}
+	w = s.do(t, http.MethodPut, path, bookBody(authorID, "978-0-7475-3269-9"), "Authorization", librarian, "If-Match", etag)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with the ETag read before the update = %d, want 412", w.Code)
+	}
+}
+
+func TestBookCollectionETags(t *testing.T) {
+	s := newTestServer(t)
+	librarian := s.user(t, models.RoleLibrarian)
+	reviewer := s.user(t, models.RoleUser)
+	authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+	bookID := s.created(t, "/api/v1/books", bookBody(authorID, "978-0-7475-3269-9"), "Authorization", librarian)
+	copyID := s.created(t, fmt.Sprintf("/api/v1/books/%d/copies", bookID), gin.H{"barcode": "LIB-1"}, "Authorization", librarian)
+
+	tests := []struct {
+		name   string
+		path   string
+		change func()
+	}{
+		{
+			name: "reviews",
+			path: fmt.Sprintf("/api/v1/books/%d/reviews", bookID),
+			change: func() {
+				s.created(t, fmt.Sprintf("/api/v1/books/%d/reviews", bookID), reviewBody(bookID, 4), "Authorization", reviewer)
+			},
+		},
+		{
+			name: "copies",
+			path: fmt.Sprintf("/api/v1/books/%d/copies", bookID),
+			change: func() {
+				w := s.do(t, http.MethodPut, fmt.Sprintf("/api/v1/books/%d/copies/%d", bookID, copyID),
+					gin.H{"barcode": "LIB-1", "status": "on_loan"}, "Authorization", librarian)
+				if w.Code != http.StatusOK {
+					t.Fatalf("PUT copy = %d: %s", w.Code, w.Body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etag := s.do(t, http.MethodGet, tt.path, nil, "Authorization", reviewer).Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("no ETag")
+			}
+			if w := s.do(t, http.MethodGet, tt.path, nil, "Authorization", reviewer, "If-None-Match", etag); w.Code != http.StatusNotModified {
+				t.Errorf("GET while unchanged = %d, want 304", w.Code)
+			}
+
+			tt.change()
+			w := s.do(t, http.MethodGet, tt.path, nil, "Authorization", reviewer, "If-None-Match", etag)
+			if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+				t.Errorf("GET after a change = %d with ETag %s, want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
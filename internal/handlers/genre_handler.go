@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errGenreExists = apperr.Conflict("genre_exists", "A genre with this name already exists")
+
+// GenreHandler serves the genre endpoints
+type GenreHandler struct {
+	genres repository.GenreRepository
+}
+
+// NewGenreHandler creates a GenreHandler backed by the given repository
+func NewGenreHandler(genres repository.GenreRepository) *GenreHandler {
+	return &GenreHandler{genres: genres}
+}
+
+// GetGenres godoc
+// @Summary Get all genres
+// @Description Get a paginated list of genres ordered by name, with the number of books in each
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedGenresResponse
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres [get]
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	genres, totalCount, err := h.genres.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(genres))
+	for i, genre := range genres {
+		ids[i] = genre.ID
+	}
+	counts, err := h.genres.CountBooks(ids)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	genreResponses := make([]dto.GenreDetailResponse, len(genres))
+	for i, genre := range genres {
+		genreResponses[i] = dto.ToGenreDetailResponse(genre, counts[genre.ID])
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedGenresResponse{
+		Data:       genreResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetGenre godoc
+// @Summary Get genre by ID
+// @Description Get a genre by ID with its number of books. List the books with GET /api/v1/books?genre={id}.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.GenreDetailResponse
+// @Header 200 {string} ETag "Changes with the genre and its number of books"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Genre not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres/{id} [get]
+func (h *GenreHandler) GetGenre(c *gin.Context) {
+	genre, bookCount, err := h.loadGenre(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, genreETag(*genre, bookCount)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToGenreDetailResponse(*genre, bookCount))
+}
+
+// loadGenre loads the genre named by the id parameter with its number of books
+func (h *GenreHandler) loadGenre(c *gin.Context) (*models.Genre, int64, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, 0, errInvalidID
+	}
+
+	genre, err := h.genres.GetByID(uint(id))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts, err := h.genres.CountBooks([]uint{genre.ID})
+	if err != nil {
+		return nil, 0, err
+	}
+	return genre, counts[genre.ID], nil
+}
+
+// CreateGenre godoc
+// @Summary Create new genre
+// @Description Create a new genre. Names are unique regardless of case. Requires the librarian or admin role.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param genre body dto.CreateGenreRequest true "Genre object that needs to be added"
+// @Success 201 {object} dto.GenreResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 409 {object} dto.ProblemResponse "A genre with this name already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var req dto.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	genre := dto.CreateGenreRequestToModel(req)
+
+	if err := h.genres.Create(&genre); err != nil {
+		if apperr.From(err).Code == errGenreExists.Code {
+			err = errGenreExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToGenreResponse(genre))
+}
+
+// UpdateGenre godoc
+// @Summary Replace genre
+// @Description Replace an existing genre. Optional fields that are left out are cleared. Requires the librarian or admin role.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Genre ID" minimum(1)
+// @Param If-Match header string false "ETag of the genre the change is based on"
+// @Param genre body dto.UpdateGenreRequest true "The new state of the genre"
+// @Success 200 {object} dto.GenreResponse
+// @Header 200 {string} ETag "ETag of the updated genre"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Genre not found"
+// @Failure 409 {object} dto.ProblemResponse "A genre with this name already exists or the genre was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The genre has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	genre, bookCount, err := h.loadGenre(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, genreETag(*genre, bookCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveGenre(c, genre, bookCount, req)
+}
+
+// PatchGenre godoc
+// @Summary Update genre
+// @Description Change some fields of a genre with a JSON Merge Patch, where null clears a field, or a JSON Patch. Requires the librarian or admin role.
+// @Tags genres
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Genre ID" minimum(1)
+// @Param If-Match header string false "ETag of the genre the change is based on"
+// @Param genre body dto.UpdateGenreRequest true "The fields to change"
+// @Success 200 {object} dto.GenreResponse
+// @Header 200 {string} ETag "ETag of the updated genre"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting genre"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Genre not found"
+// @Failure 409 {object} dto.ProblemResponse "A genre with this name already exists, a test operation failed or the genre was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The genre has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres/{id} [patch]
+func (h *GenreHandler) PatchGenre(c *gin.Context) {
+	genre, bookCount, err := h.loadGenre(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, genreETag(*genre, bookCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateGenreRequest
+	if err := bindPatch(c, dto.ToUpdateGenreRequest(*genre), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveGenre(c, genre, bookCount, req)
+}
+
+// saveGenre replaces genre with req and responds with the result
+func (h *GenreHandler) saveGenre(c *gin.Context, genre *models.Genre, bookCount int64, req dto.UpdateGenreRequest) {
+	// Update model from DTO
+	dto.UpdateGenreModelFromRequest(genre, req)
+
+	if err := h.genres.Update(genre); err != nil {
+		if apperr.From(err).Code == errGenreExists.Code {
+			err = errGenreExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", genreETag(*genre, bookCount))
+	c.JSON(http.StatusOK, dto.ToGenreResponse(*genre))
+}
+
+// DeleteGenre godoc
+// @Summary Delete genre
+// @Description Delete a genre and take it off its books, which are kept. Unlike books, authors and reviews, genres are not soft deleted and cannot be restored. Requires the librarian or admin role.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Genre ID" minimum(1)
+// @Param If-Match header string false "ETag of the genre the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Genre not found, for conditional deletes"
+// @Failure 412 {object} dto.ProblemResponse "The genre has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current genre
+	if c.GetHeader("If-Match") != "" {
+		genre, bookCount, err := h.loadGenre(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, genreETag(*genre, bookCount)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.genres.Delete(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/repository"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cursorPagination reports whether a listing is requested in cursor mode,
+// either with pagination=cursor for the first page or with a cursor token
+func cursorPagination(c *gin.Context) bool {
+	return c.Query("pagination") == "cursor" || c.Query("cursor") != ""
+}
+
+// parseCursor decodes the cursor query parameter, nil means the first page
+func parseCursor(c *gin.Context) (*repository.Cursor, error) {
+	token := c.Query("cursor")
+	if token == "" {
+		return nil, nil
+	}
+	cursor, err := repository.DecodeCursor(token)
+	if err != nil {
+		return nil, apperr.Validation("invalid_cursor", err.Error())
+	}
+	return cursor, nil
+}
+
+// parsePageSize reads page_size with the same default and bounds as GetBooks
+func parsePageSize(c *gin.Context) int {
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return pageSize
+}
+
+// encodeCursor returns the token of a cursor or "" when there is none
+func encodeCursor(cursor *repository.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.Encode()
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/patch"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Media types accepted by PATCH endpoints. Plain application/json is treated as a merge patch.
+const (
+	mediaTypeMergePatch = "application/merge-patch+json"
+	mediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var errUnsupportedPatch = apperr.UnsupportedMediaType("unsupported_patch_format",
+	"PATCH bodies must be "+mediaTypeMergePatch+" or "+mediaTypeJSONPatch)
+
+// bindPatch applies the PATCH request body to current, the resource in the form
+// of its PUT request, then decodes and validates the result into req like a PUT
+// body. Fields the patch leaves out keep their current values, while fields set
+// to null in a merge patch or removed by a JSON Patch are cleared.
+func bindPatch(c *gin.Context, current, req any) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return apperr.Validation("invalid_body", "Could not read request body")
+	}
+
+	var patched []byte
+	switch c.ContentType() {
+	case mediaTypeMergePatch, binding.MIMEJSON:
+		patched, err = patch.Merge(doc, body)
+	case mediaTypeJSONPatch:
+		patched, err = patch.Apply(doc, body)
+	default:
+		c.Header("Accept-Patch", mediaTypeMergePatch+", "+mediaTypeJSONPatch)
+		return errUnsupportedPatch
+	}
+	if errors.Is(err, patch.ErrTestFailed) {
+		return apperr.Conflict("patch_test_failed", err.Error())
+	}
+	if err != nil {
+		return apperr.Validation("invalid_patch", err.Error())
+	}
+
+	// Read-only or misspelled fields must not be silently dropped
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field := apperr.FieldError{Field: strings.Trim(name, `"`), Rule: "unknown", Message: "is not a field that can be changed"}
+			return apperr.Validation("validation_failed", "Request body failed validation", field)
+		}
+		return bindingError(err)
+	}
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return bindingError(err)
+	}
+	return nil
+}
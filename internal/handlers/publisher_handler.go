@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Publisher errors reported in terms of the request
+var (
+	errPublisherExists      = apperr.Conflict("publisher_exists", "A publisher with this name already exists")
+	errPublisherHasEditions = apperr.Conflict("publisher_has_editions", "Publisher has editions, change their publisher first")
+)
+
+// PublisherHandler serves the publisher endpoints
+type PublisherHandler struct {
+	publishers repository.PublisherRepository
+}
+
+// NewPublisherHandler creates a PublisherHandler backed by the given repository
+func NewPublisherHandler(publishers repository.PublisherRepository) *PublisherHandler {
+	return &PublisherHandler{publishers: publishers}
+}
+
+// GetPublishers godoc
+// @Summary Get all publishers
+// @Description Get a paginated list of publishers ordered by name, with the number of editions in each
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedPublishersResponse
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers [get]
+func (h *PublisherHandler) GetPublishers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	publishers, totalCount, err := h.publishers.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(publishers))
+	for i, publisher := range publishers {
+		ids[i] = publisher.ID
+	}
+	counts, err := h.publishers.CountEditions(ids)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	publisherResponses := make([]dto.PublisherDetailResponse, len(publishers))
+	for i, publisher := range publishers {
+		publisherResponses[i] = dto.ToPublisherDetailResponse(publisher, counts[publisher.ID])
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedPublishersResponse{
+		Data:       publisherResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetPublisher godoc
+// @Summary Get publisher by ID
+// @Description Get a publisher by ID with its number of editions
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param id path int true "Publisher ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.PublisherDetailResponse
+// @Header 200 {string} ETag "Changes with the publisher and its number of editions"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Publisher not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers/{id} [get]
+func (h *PublisherHandler) GetPublisher(c *gin.Context) {
+	publisher, editionCount, err := h.loadPublisher(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, publisherETag(*publisher, editionCount)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPublisherDetailResponse(*publisher, editionCount))
+}
+
+// loadPublisher loads the publisher named by the id parameter with its number of editions
+func (h *PublisherHandler) loadPublisher(c *gin.Context) (*models.Publisher, int64, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, 0, errInvalidID
+	}
+
+	publisher, err := h.publishers.GetByID(uint(id))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts, err := h.publishers.CountEditions([]uint{publisher.ID})
+	if err != nil {
+		return nil, 0, err
+	}
+	return publisher, counts[publisher.ID], nil
+}
+
+// CreatePublisher godoc
+// @Summary Create new publisher
+// @Description Create a new publisher. Names are unique regardless of case. Requires the librarian or admin role.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param publisher body dto.CreatePublisherRequest true "Publisher object that needs to be added"
+// @Success 201 {object} dto.PublisherResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 409 {object} dto.ProblemResponse "A publisher with this name already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers [post]
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var req dto.CreatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	publisher := dto.CreatePublisherRequestToModel(req)
+
+	if err := h.publishers.Create(&publisher); err != nil {
+		if apperr.From(err).Code == errPublisherExists.Code {
+			err = errPublisherExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToPublisherResponse(publisher))
+}
+
+// UpdatePublisher godoc
+// @Summary Replace publisher
+// @Description Replace an existing publisher, which renames it. Requires the librarian or admin role.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Publisher ID" minimum(1)
+// @Param If-Match header string false "ETag of the publisher the change is based on"
+// @Param publisher body dto.UpdatePublisherRequest true "The new state of the publisher"
+// @Success 200 {object} dto.PublisherResponse
+// @Header 200 {string} ETag "ETag of the updated publisher"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Publisher not found"
+// @Failure 409 {object} dto.ProblemResponse "A publisher with this name already exists or the publisher was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The publisher has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers/{id} [put]
+func (h *PublisherHandler) UpdatePublisher(c *gin.Context) {
+	publisher, editionCount, err := h.loadPublisher(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, publisherETag(*publisher, editionCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.savePublisher(c, publisher, editionCount, req)
+}
+
+// PatchPublisher godoc
+// @Summary Update publisher
+// @Description Rename a publisher with a JSON Merge Patch or a JSON Patch. Requires the librarian or admin role.
+// @Tags publishers
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Publisher ID" minimum(1)
+// @Param If-Match header string false "ETag of the publisher the change is based on"
+// @Param publisher body dto.UpdatePublisherRequest true "The fields to change"
+// @Success 200 {object} dto.PublisherResponse
+// @Header 200 {string} ETag "ETag of the updated publisher"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting publisher"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Publisher not found"
+// @Failure 409 {object} dto.ProblemResponse "A publisher with this name already exists, a test operation failed or the publisher was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The publisher has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers/{id} [patch]
+func (h *PublisherHandler) PatchPublisher(c *gin.Context) {
+	publisher, editionCount, err := h.loadPublisher(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, publisherETag(*publisher, editionCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdatePublisherRequest
+	if err := bindPatch(c, dto.ToUpdatePublisherRequest(*publisher), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.savePublisher(c, publisher, editionCount, req)
+}
+
+// savePublisher replaces publisher with req and responds with the result
+func (h *PublisherHandler) savePublisher(c *gin.Context, publisher *models.Publisher, editionCount int64, req dto.UpdatePublisherRequest) {
+	// Update model from DTO
+	dto.UpdatePublisherModelFromRequest(publisher, req)
+
+	if err := h.publishers.Update(publisher); err != nil {
+		if apperr.From(err).Code == errPublisherExists.Code {
+			err = errPublisherExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", publisherETag(*publisher, editionCount))
+	c.JSON(http.StatusOK, dto.ToPublisherResponse(*publisher))
+}
+
+// DeletePublisher godoc
+// @Summary Delete publisher
+// @Description Delete a publisher that has no editions. Editions of deleted books lose their publisher. Unlike books, authors and reviews, publishers are not soft deleted and cannot be restored. Requires the librarian or admin role.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Publisher ID" minimum(1)
+// @Param If-Match header string false "ETag of the publisher the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Publisher not found, for conditional deletes"
+// @Failure 409 {object} dto.ProblemResponse "The publisher has editions, listed as dependents"
+// @Failure 412 {object} dto.ProblemResponse "The publisher has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/publishers/{id} [delete]
+func (h *PublisherHandler) DeletePublisher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current publisher
+	if c.GetHeader("If-Match") != "" {
+		publisher, editionCount, err := h.loadPublisher(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, publisherETag(*publisher, editionCount)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.publishers.Delete(uint(id)); err != nil {
+		// Editions are the only records that refer to publishers
+		if apperr.From(err).Code == "publisher_in_use" {
+			err = h.publisherHasEditions(uint(id))
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// publisherHasEditions builds the conflict for deleting a publisher with
+// editions, listing them by ISBN
+func (h *PublisherHandler) publisherHasEditions(id uint) error {
+	editions, err := h.publishers.GetEditions(id)
+	if err != nil {
+		return err
+	}
+
+	dependents := make([]apperr.Dependent, len(editions))
+	for i, edition := range editions {
+		dependents[i] = apperr.Dependent{Resource: "edition", ID: edition.ID, Name: edition.ISBN}
+	}
+	return apperr.Conflict(errPublisherHasEditions.Code, errPublisherHasEditions.Message, dependents...)
+}
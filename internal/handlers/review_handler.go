@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"go-rest-api/internal/apperr"
 	"go-rest-api/internal/dto"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
 	"go-rest-api/internal/repository"
 	"net/http"
 	"strconv"
@@ -9,35 +12,82 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Review errors reported in terms of the request
+var (
+	errNotReviewAuthor  = apperr.Forbidden("not_review_author", "Not the author of the review")
+	errReviewNotDeleted = apperr.Conflict("review_not_deleted", "Review is not deleted")
+	errBookDeleted      = apperr.Conflict("book_deleted", "The review's book is deleted, restore the book first")
+)
+
+// ReviewHandler serves the review endpoints
+type ReviewHandler struct {
+	reviews repository.ReviewRepository
+	books   repository.BookRepository
+}
+
+// NewReviewHandler creates a ReviewHandler backed by the given repositories
+func NewReviewHandler(reviews repository.ReviewRepository, books repository.BookRepository) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews, books: books}
+}
+
 // GetBookReviews godoc
 // @Summary Get reviews for a book
-// @Description Get all reviews for a specific book
+// @Description Get all reviews for a specific book. With pagination=cursor the reviews are keyset paginated by ID.
 // @Tags reviews
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID" minimum(1)
+// @Param pagination query string false "Set to cursor for keyset pagination" Enums(cursor)
+// @Param cursor query string false "Opaque next_cursor or prev_cursor from a previous cursor page, implies pagination=cursor"
+// @Param page_size query int false "Number of items per cursor page" minimum(1) maximum(100) default(10)
+// @Param include_deleted query bool false "Also list deleted reviews, which have a deleted_at, and allow a deleted book. Admins only."
+// @Param If-None-Match header string false "ETag of a cached list, without pagination=cursor"
 // @Success 200 {array} dto.ReviewResponse
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 404 {object} map[string]string "Book not found"
-// @Failure 500 {object} map[string]string "Server error"
+// @Success 200 {object} dto.CursorReviewsResponse "Returns cursor paginated reviews when pagination=cursor"
+// @Header 200 {string} ETag "Changes with the reviews of the book, without pagination=cursor"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or cursor"
+// @Failure 401 {object} dto.ProblemResponse "include_deleted without authentication"
+// @Failure 403 {object} dto.ProblemResponse "include_deleted by a caller that is not an admin"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books/{id}/reviews [get]
-func GetBookReviews(c *gin.Context) {
+func (h *ReviewHandler) GetBookReviews(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	// Verify that the book exists
-	_, err = repository.GetBookByID(uint(id))
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Verify that the book exists, the reviews of a deleted book can be
+	// listed together with the deleted reviews
+	_, err = h.books.GetByID(uint(id))
+	if includeDeleted && apperr.KindOf(err) == apperr.KindNotFound {
+		_, err = h.books.GetDeletedByID(uint(id))
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		abortWithError(c, err)
 		return
 	}
 
-	reviews, err := repository.GetReviewsByBookID(uint(id))
+	if cursorPagination(c) {
+		h.getBookReviewsByCursor(c, uint(id), includeDeleted)
+		return
+	}
+
+	reviews, err := h.reviews.GetByBookID(uint(id), includeDeleted)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, reviewsETag(reviews)) {
 		return
 	}
 
@@ -50,44 +100,117 @@ func GetBookReviews(c *gin.Context) {
 	c.JSON(http.StatusOK, reviewResponses)
 }
 
+// getBookReviewsByCursor serves GetBookReviews in cursor mode
+func (h *ReviewHandler) getBookReviewsByCursor(c *gin.Context, bookID uint, includeDeleted bool) {
+	cursor, err := parseCursor(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	pageSize := parsePageSize(c)
+	reviews, page, err := h.reviews.GetByBookIDCursor(bookID, cursor, pageSize, includeDeleted)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	reviewResponses := make([]dto.ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		reviewResponses[i] = dto.ToReviewResponse(review)
+	}
+
+	c.JSON(http.StatusOK, dto.CursorReviewsResponse{
+		Data:       reviewResponses,
+		NextCursor: encodeCursor(page.Next),
+		PrevCursor: encodeCursor(page.Prev),
+		PageSize:   pageSize,
+	})
+}
+
+// GetReview godoc
+// @Summary Get review by ID
+// @Description Get a single review, with the ETag needed for conditional updates
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.ReviewResponse
+// @Header 200 {string} ETag "Changes with the review"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Review not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/reviews/{id} [get]
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	review, err := h.reviews.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, reviewETag(*review)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReviewResponse(*review))
+}
+
 // AddReview godoc
 // @Summary Add review to book
 // @Description Add a new review to a book
 // @Tags reviews
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Book ID" minimum(1)
 // @Param review body dto.CreateReviewRequest true "Review object that needs to be added"
 // @Success 201 {object} dto.ReviewResponse
-// @Failure 400 {object} map[string]string "Invalid ID format or request body"
-// @Failure 404 {object} map[string]string "Book not found"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 404 {object} dto.ProblemResponse "Book not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/books/{id}/reviews [post]
-func AddReview(c *gin.Context) {
+func (h *ReviewHandler) AddReview(c *gin.Context) {
 	bookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
 	// Verify that the book exists
-	_, err = repository.GetBookByID(uint(bookID))
+	_, err = h.books.GetByID(uint(bookID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		abortWithError(c, err)
 		return
 	}
 
 	var req dto.CreateReviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abortWithError(c, bindingError(err))
 		return
 	}
 
+	// Reviews posted with an API key have no owner
+	var userID *uint
+	if id, ok := middleware.CurrentUserID(c); ok {
+		userID = &id
+	}
+
 	// Convert DTO to model
-	review := dto.CreateReviewRequestToModel(req, uint(bookID))
+	review := dto.CreateReviewRequestToModel(req, uint(bookID), userID)
 
-	if err := repository.CreateReview(&review); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.reviews.Create(&review); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
@@ -95,70 +218,227 @@ func AddReview(c *gin.Context) {
 }
 
 // UpdateReview godoc
-// @Summary Update review
-// @Description Update an existing review
+// @Summary Replace review
+// @Description Replace an existing review. Only its author or an admin may update it.
 // @Tags reviews
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Review ID" minimum(1)
-// @Param review body dto.UpdateReviewRequest true "Review object that needs to be updated"
+// @Param If-Match header string false "ETag of the review the change is based on"
+// @Param review body dto.UpdateReviewRequest true "The new state of the review"
 // @Success 200 {object} dto.ReviewResponse
-// @Failure 400 {object} map[string]string "Invalid ID format or request body"
-// @Failure 404 {object} map[string]string "Review not found"
-// @Failure 500 {object} map[string]string "Server error"
+// @Header 200 {string} ETag "ETag of the updated review"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Not the author of the review"
+// @Failure 404 {object} dto.ProblemResponse "Review not found"
+// @Failure 409 {object} dto.ProblemResponse "The review was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The review has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/reviews/{id} [put]
-func UpdateReview(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	review, ok := h.modifiableReview(c)
+	if !ok {
 		return
 	}
 
-	review, err := repository.GetReviewByID(uint(id))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+	var req dto.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveReview(c, review, req)
+}
+
+// PatchReview godoc
+// @Summary Update review
+// @Description Change some fields of a review with a JSON Merge Patch, where null clears a field, or a JSON Patch. Only its author or an admin may update it.
+// @Tags reviews
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Review ID" minimum(1)
+// @Param If-Match header string false "ETag of the review the change is based on"
+// @Param review body dto.UpdateReviewRequest true "The fields to change"
+// @Success 200 {object} dto.ReviewResponse
+// @Header 200 {string} ETag "ETag of the updated review"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting review"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Not the author of the review"
+// @Failure 404 {object} dto.ProblemResponse "Review not found"
+// @Failure 409 {object} dto.ProblemResponse "A test operation failed or the review was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The review has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/reviews/{id} [patch]
+func (h *ReviewHandler) PatchReview(c *gin.Context) {
+	review, ok := h.modifiableReview(c)
+	if !ok {
 		return
 	}
 
 	var req dto.UpdateReviewRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindPatch(c, dto.ToUpdateReviewRequest(*review), &req); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	h.saveReview(c, review, req)
+}
+
+// modifiableReview loads the review in the path and checks that the caller may
+// change it and that it matches If-Match, aborting the request otherwise
+func (h *ReviewHandler) modifiableReview(c *gin.Context) (*models.Review, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return nil, false
+	}
+
+	review, err := h.reviews.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return nil, false
+	}
+
+	if !canModifyReview(c, *review) {
+		abortWithError(c, errNotReviewAuthor)
+		return nil, false
+	}
+
+	if err := checkIfMatch(c, reviewETag(*review)); err != nil {
+		abortWithError(c, err)
+		return nil, false
+	}
+	return review, true
+}
+
+// saveReview replaces review with req and responds with the result
+func (h *ReviewHandler) saveReview(c *gin.Context, review *models.Review, req dto.UpdateReviewRequest) {
 	// Update model from DTO
 	dto.UpdateReviewModelFromRequest(review, req)
 
-	if err := repository.UpdateReview(review); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.reviews.Update(review); err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", reviewETag(*review))
 	c.JSON(http.StatusOK, dto.ToReviewResponse(*review))
 }
 
 // DeleteReview godoc
 // @Summary Delete review
-// @Description Delete a review
+// @Description Delete a review. Only its author or an admin may delete it.
 // @Tags reviews
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "Review ID" minimum(1)
+// @Param If-Match header string false "ETag of the review the deletion is based on"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string "Invalid ID format"
-// @Failure 500 {object} map[string]string "Server error"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Not the author of the review"
+// @Failure 404 {object} dto.ProblemResponse "Review not found"
+// @Failure 412 {object} dto.ProblemResponse "The review has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
 // @Router /api/v1/reviews/{id} [delete]
-func DeleteReview(c *gin.Context) {
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	review, ok := h.modifiableReview(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviews.Delete(review.ID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RestoreReview godoc
+// @Summary Restore deleted review
+// @Description Undo the deletion of a review whose book is not deleted. Only its author or an admin may restore it.
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Review ID" minimum(1)
+// @Success 200 {object} dto.ReviewResponse
+// @Header 200 {string} ETag "ETag of the restored review"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Not the author of the review"
+// @Failure 404 {object} dto.ProblemResponse "Review not found"
+// @Failure 409 {object} dto.ProblemResponse "The review is not deleted or its book is deleted"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/reviews/{id}/restore [post]
+func (h *ReviewHandler) RestoreReview(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		abortWithError(c, errInvalidID)
 		return
 	}
 
-	if err := repository.DeleteReview(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	deleted, err := h.reviews.GetDeletedByID(uint(id))
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		// Tell a live review apart from one that does not exist
+		if _, liveErr := h.reviews.GetByID(uint(id)); liveErr == nil {
+			err = errReviewNotDeleted
+		}
+	}
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	if !canModifyReview(c, *deleted) {
+		abortWithError(c, errNotReviewAuthor)
+		return
+	}
+
+	// Reviews of a deleted book come back by restoring the book
+	if _, err := h.books.GetByID(deleted.BookID); err != nil {
+		abortWithError(c, replaceNotFound(err, errBookDeleted))
+		return
+	}
+
+	if err := h.reviews.Restore(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	review, err := h.reviews.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", reviewETag(*review))
+	c.JSON(http.StatusOK, dto.ToReviewResponse(*review))
+}
+
+// canModifyReview reports whether the caller is the review's author, an admin
+// or an API key, whose reviews:write scope is checked by the route.
+// Reviews without an author can only be changed by admins and API keys.
+func canModifyReview(c *gin.Context, review models.Review) bool {
+	identity, ok := middleware.CurrentIdentity(c)
+	if !ok {
+		return false
+	}
+	if identity.IsAPIKey() || identity.Role == models.RoleAdmin {
+		return true
+	}
+	return review.UserID != nil && *review.UserID == identity.UserID
 }
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler serves the search endpoint
+type SearchHandler struct {
+	search repository.SearchRepository
+}
+
+// NewSearchHandler creates a SearchHandler backed by the given repository
+func NewSearchHandler(search repository.SearchRepository) *SearchHandler {
+	return &SearchHandler{search: search}
+}
+
+// Search godoc
+// @Summary Search books and authors
+// @Description Full-text search over book titles, descriptions and author names, ordered by relevance. A query that is an ISBN-10 or ISBN-13 also finds the book with an edition of that ISBN. Highlights are HTML: matched terms are wrapped in <mark> tags and the rest is escaped.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param type query string false "Restrict results to one type" Enums(book, author)
+// @Param limit query int false "Maximum number of results" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.SearchResponse
+// @Failure 400 {object} dto.ProblemResponse "Missing query or invalid type"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		abortWithError(c, apperr.Validation("missing_query", "Query parameter q is required"))
+		return
+	}
+
+	kind := c.Query("type")
+	if kind != "" && kind != repository.SearchTypeBook && kind != repository.SearchTypeAuthor {
+		abortWithError(c, apperr.Validation("invalid_query", "Invalid type, expected book or author"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	hits, err := h.search.Search(query, kind, limit)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert search hits to DTOs
+	results := make([]dto.SearchResultResponse, len(hits))
+	for i, hit := range hits {
+		results[i] = dto.ToSearchResultResponse(hit)
+	}
+
+	c.JSON(http.StatusOK, dto.SearchResponse{
+		Query:   query,
+		Results: results,
+		Total:   len(results),
+	})
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errSeriesHasBooks = apperr.Conflict("series_has_books", "Series has books, take them out of the series first")
+
+// SeriesHandler serves the series endpoints
+type SeriesHandler struct {
+	series repository.SeriesRepository
+}
+
+// NewSeriesHandler creates a SeriesHandler backed by the given repository
+func NewSeriesHandler(series repository.SeriesRepository) *SeriesHandler {
+	return &SeriesHandler{series: series}
+}
+
+// GetAllSeries godoc
+// @Summary Get all series
+// @Description Get a paginated list of series ordered by name, with the number of books in each
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedSeriesResponse
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series [get]
+func (h *SeriesHandler) GetAllSeries(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	all, totalCount, err := h.series.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(all))
+	for i, series := range all {
+		ids[i] = series.ID
+	}
+	counts, err := h.series.CountBooks(ids)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	seriesResponses := make([]dto.SeriesSummaryResponse, len(all))
+	for i, series := range all {
+		seriesResponses[i] = dto.ToSeriesSummaryResponse(series, counts[series.ID])
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedSeriesResponse{
+		Data:       seriesResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetSeries godoc
+// @Summary Get series by ID
+// @Description Get a series by ID with its books in reading order, by series_position
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.SeriesDetailResponse
+// @Header 200 {string} ETag "Changes with the series and its books"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Series not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series/{id} [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series, books, err := h.loadSeries(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, seriesETag(*series, books)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSeriesDetailResponse(*series, books))
+}
+
+// loadSeries loads the series named by the id parameter with its books in reading order
+func (h *SeriesHandler) loadSeries(c *gin.Context) (*models.Series, []models.Book, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, errInvalidID
+	}
+
+	series, err := h.series.GetByID(uint(id))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	books, err := h.series.GetBooks(series.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, books, nil
+}
+
+// CreateSeries godoc
+// @Summary Create new series
+// @Description Create a new series. Add books to it with the series_id and series_position of the book. Requires the librarian or admin role.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param series body dto.CreateSeriesRequest true "Series object that needs to be added"
+// @Success 201 {object} dto.SeriesResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series [post]
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req dto.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	series := dto.CreateSeriesRequestToModel(req)
+
+	if err := h.series.Create(&series); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToSeriesResponse(series))
+}
+
+// UpdateSeries godoc
+// @Summary Replace series
+// @Description Replace an existing series. Optional fields that are left out are cleared. Requires the librarian or admin role.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Series ID" minimum(1)
+// @Param If-Match header string false "ETag of the series the change is based on"
+// @Param series body dto.UpdateSeriesRequest true "The new state of the series"
+// @Success 200 {object} dto.SeriesResponse
+// @Header 200 {string} ETag "ETag of the updated series"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Series not found"
+// @Failure 409 {object} dto.ProblemResponse "The series was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The series has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	series, books, err := h.loadSeries(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, seriesETag(*series, books)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveSeries(c, series, books, req)
+}
+
+// PatchSeries godoc
+// @Summary Update series
+// @Description Change some fields of a series with a JSON Merge Patch, where null clears a field, or a JSON Patch. Requires the librarian or admin role.
+// @Tags series
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Series ID" minimum(1)
+// @Param If-Match header string false "ETag of the series the change is based on"
+// @Param series body dto.UpdateSeriesRequest true "The fields to change"
+// @Success 200 {object} dto.SeriesResponse
+// @Header 200 {string} ETag "ETag of the updated series"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting series"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Series not found"
+// @Failure 409 {object} dto.ProblemResponse "A test operation failed or the series was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The series has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series/{id} [patch]
+func (h *SeriesHandler) PatchSeries(c *gin.Context) {
+	series, books, err := h.loadSeries(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, seriesETag(*series, books)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := bindPatch(c, dto.ToUpdateSeriesRequest(*series), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveSeries(c, series, books, req)
+}
+
+// saveSeries replaces series with req and responds with the result
+func (h *SeriesHandler) saveSeries(c *gin.Context, series *models.Series, books []models.Book, req dto.UpdateSeriesRequest) {
+	// Update model from DTO
+	dto.UpdateSeriesModelFromRequest(series, req)
+
+	if err := h.series.Update(series); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", seriesETag(*series, books))
+	c.JSON(http.StatusOK, dto.ToSeriesResponse(*series))
+}
+
+// DeleteSeries godoc
+// @Summary Delete series
+// @Description Delete a series that has no books. Deleted books in the series are taken out of it. Unlike books, authors and reviews, series are not soft deleted and cannot be restored. Requires the librarian or admin role.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Series ID" minimum(1)
+// @Param If-Match header string false "ETag of the series the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Series not found, for conditional deletes"
+// @Failure 409 {object} dto.ProblemResponse "The series has books, listed as dependents"
+// @Failure 412 {object} dto.ProblemResponse "The series has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current series
+	if c.GetHeader("If-Match") != "" {
+		series, books, err := h.loadSeries(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, seriesETag(*series, books)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.series.Delete(uint(id)); err != nil {
+		// Books are the only records that refer to series
+		if apperr.From(err).Code == "series_in_use" {
+			err = h.seriesHasBooks(uint(id))
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// seriesHasBooks builds the conflict for deleting a series with books, listing them in reading order
+func (h *SeriesHandler) seriesHasBooks(id uint) error {
+	books, err := h.series.GetBooks(id)
+	if err != nil {
+		return err
+	}
+
+	dependents := make([]apperr.Dependent, len(books))
+	for i, book := range books {
+		dependents[i] = apperr.Dependent{Resource: "book", ID: book.ID, Name: book.Title}
+	}
+	return apperr.Conflict(errSeriesHasBooks.Code, errSeriesHasBooks.Message, dependents...)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"go-rest-api/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestCreateBookInSeries(t *testing.T) {
+	s := newTestServer(t)
+	librarian := s.user(t, models.RoleLibrarian)
+	authorID := s.created(t, "/api/v1/authors", authorBody("Author"), "Authorization", librarian)
+	series := &models.Series{Name: "Series"}
+	if err := s.store.Series().Create(series); err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	// The schema allows books in a series without a position
+	if err := s.store.Books().Create(&models.Book{Title: "Unplaced", AuthorID: authorID, ISBN: "unplaced", SeriesID: &series.ID}); err != nil {
+		t.Fatalf("create book: %v", err)
+	}
+
+	inSeries := func(isbn string) map[string]any {
+		body := bookBody(authorID, isbn)
+		body["series_id"], body["series_position"] = series.ID, 1
+		return body
+	}
+	s.created(t, "/api/v1/books", inSeries("0-7475-3269-9"), "Authorization", librarian)
+
+	w := s.do(t, http.MethodPost, "/api/v1/books", inSeries("978-0-306-40615-7"), "Authorization", librarian)
+	if w.Code != http.StatusConflict || errorCode(w) != "series_position_taken" {
+		t.Errorf("create at a taken position = %d %q, want 409 series_position_taken", w.Code, errorCode(w))
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testServer serves the catalog routes of main.go, with the same middleware,
+// from a memory store
+type testServer struct {
+	store  *repository.MemoryStore
+	tokens *auth.TokenService
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	s := &testServer{
+		store:  repository.NewMemoryStore(),
+		tokens: auth.NewTokenService([]byte("test secret"), time.Minute, time.Hour),
+		router: gin.New(),
+	}
+	store := s.store
+	books := NewBookHandler(store.Books(), store.Authors(), store.Genres(), store.Tags(), store.Subjects(), store.Series())
+	authors := NewAuthorHandler(store.Authors())
+	reviews := NewReviewHandler(store.Reviews(), store.Books())
+	copies := NewCopyHandler(store.Copies(), store.Books())
+
+	booksWriter := middleware.Authorize(auth.ScopeBooksWrite, models.RoleLibrarian, models.RoleAdmin)
+	authorsWriter := middleware.Authorize(auth.ScopeAuthorsWrite, models.RoleLibrarian, models.RoleAdmin)
+	reviewsWriter := middleware.RequireScope(auth.ScopeReviewsWrite)
+
+	s.router.Use(middleware.RequestID(), middleware.ErrorHandler())
+	api := s.router.Group("/api/v1", middleware.Authenticate(s.tokens, store.APIKeys()))
+	api.GET("/books/:id", middleware.RequireScope(auth.ScopeBooksRead), books.GetBook)
+	api.POST("/books", booksWriter, books.CreateBook)
+	api.PUT("/books/:id", booksWriter, books.UpdateBook)
+	api.DELETE("/books/:id", booksWriter, books.DeleteBook)
+	api.GET("/books/:id/reviews", middleware.RequireScope(auth.ScopeReviewsRead), reviews.GetBookReviews)
+	api.POST("/books/:id/reviews", reviewsWriter, reviews.AddReview)
+	api.GET("/books/:id/copies", middleware.RequireScope(auth.ScopeBooksRead), copies.GetBookCopies)
+	api.POST("/books/:id/copies", booksWriter, copies.AddCopy)
+	api.PUT("/books/:id/copies/:copy_id", booksWriter, copies.UpdateCopy)
+	api.GET("/authors/:id", middleware.RequireScope(auth.ScopeAuthorsRead), authors.GetAuthor)
+	api.POST("/authors", authorsWriter, authors.CreateAuthor)
+	api.DELETE("/authors/:id", authorsWriter, authors.DeleteAuthor)
+	api.PUT("/reviews/:id", reviewsWriter, reviews.UpdateReview)
+	api.DELETE("/reviews/:id", reviewsWriter, reviews.DeleteReview)
+	return s
+}
+
+// user saves a user with a role and returns an Authorization header value for them
+func (s *testServer) user(t *testing.T, role string) string {
+	t.Helper()
+	user := &models.User{Email: fmt.Sprintf("%s%d@example.com", role, time.Now().UnixNano()), Role: role}
+	if err := s.store.Users().Create(user); err != nil {
+		t.Fatalf("create %s: %v", role, err)
+	}
+	pair, err := s.tokens.Issue(auth.Identity{UserID: user.ID, Role: role})
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	return "Bearer " + pair.AccessToken
+}
+
+// apiKey saves an API key with scopes and returns the key
+func (s *testServer) apiKey(t *testing.T, scopes ...string) (string, *models.APIKey) {
+	t.Helper()
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("generate API key: %v", err)
+	}
+	apiKey := &models.APIKey{Name: "test", Prefix: prefix, KeyHash: auth.HashAPIKey(key), Scopes: strings.Join(scopes, " ")}
+	if err := s.store.APIKeys().Create(apiKey); err != nil {
+		t.Fatalf("create API key: %v", err)
+	}
+	return key, apiKey
+}
+
+// do sends a request with a JSON body, unless body is nil, and the given
+// headers as name, value pairs
+func (s *testServer) do(t *testing.T, method, path string, body any, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// created sends a request that must answer 201 and returns the new ID
+func (s *testServer) created(t *testing.T, path string, body any, headers ...string) uint {
+	t.Helper()
+	w := s.do(t, http.MethodPost, path, body, headers...)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST %s = %d: %s", path, w.Code, w.Body)
+	}
+	var response struct {
+		ID uint `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+	return response.ID
+}
+
+// errorCode returns the code of a problem response
+func errorCode(w *httptest.ResponseRecorder) string {
+	var problem struct {
+		Code string `json:"code"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &problem)
+	return problem.Code
+}
+
+func authorBody(name string) gin.H {
+	return gin.H{"name": name, "biography": "Biography", "birth_date": "1950-01-01"}
+}
+
+func bookBody(authorID uint, isbn string) gin.H {
+	return gin.H{"title": "Title", "author_id": authorID, "isbn": isbn, "publication_year": 2000, "description": "Description"}
+}
+
+func reviewBody(bookID uint, rating int) gin.H {
+	return gin.H{"rating": rating, "comment": "Comment", "book_id": bookID}
+}
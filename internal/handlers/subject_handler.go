@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"fmt"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Subject errors reported in terms of the request
+var (
+	errSubjectExists       = apperr.Conflict("subject_exists", "A subject with this code already exists")
+	errSubjectHasChildren  = apperr.Conflict("subject_has_children", "Subject has child subjects, move or delete them first")
+	errSubjectDoesNotExist = apperr.Validation("subject_not_found", "Subject does not exist")
+)
+
+// SubjectHandler serves the subject endpoints
+type SubjectHandler struct {
+	subjects repository.SubjectRepository
+}
+
+// NewSubjectHandler creates a SubjectHandler backed by the given repository
+func NewSubjectHandler(subjects repository.SubjectRepository) *SubjectHandler {
+	return &SubjectHandler{subjects: subjects}
+}
+
+// GetSubjects godoc
+// @Summary Get subjects
+// @Description Get a paginated list of the top level subjects, or of the children of parent_id, ordered by code. Browse the whole tree below a subject with GET /api/v1/subjects/{id}/tree.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param parent_id query int false "Only the children of this subject" minimum(1)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedSubjectsResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid or unknown parent_id"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects [get]
+func (h *SubjectHandler) GetSubjects(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	var parentID *uint
+	if value := c.Query("parent_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			abortWithError(c, invalidQuery(fmt.Errorf("invalid parent_id %q", value)))
+			return
+		}
+		parent, err := h.subjects.GetByID(uint(id))
+		if err != nil {
+			abortWithError(c, replaceNotFound(err, apperr.Validation("invalid_query", fmt.Sprintf("unknown parent_id %d", id))))
+			return
+		}
+		parentID = &parent.ID
+	}
+
+	subjects, totalCount, err := h.subjects.GetChildren(parentID, page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	subjectResponses := make([]dto.SubjectResponse, len(subjects))
+	for i, subject := range subjects {
+		subjectResponses[i] = dto.ToSubjectResponse(subject)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedSubjectsResponse{
+		Data:       subjectResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetSubject godoc
+// @Summary Get subject by ID
+// @Description Get a subject by ID with its ancestors, from the top level down. List the books filed under it or its descendants with GET /api/v1/books?subject={id}.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param id path int true "Subject ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.SubjectDetailResponse
+// @Header 200 {string} ETag "Changes with the subject and its ancestors"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id} [get]
+func (h *SubjectHandler) GetSubject(c *gin.Context) {
+	subject, ancestors, err := h.loadSubject(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, subjectETag(*subject, ancestors)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSubjectDetailResponse(*subject, ancestors))
+}
+
+// GetSubjectTree godoc
+// @Summary Get subject tree
+// @Description Get a subject with all of its descendants nested as children, each level ordered by code
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param id path int true "Subject ID" minimum(1)
+// @Success 200 {object} dto.SubjectTreeResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id}/tree [get]
+func (h *SubjectHandler) GetSubjectTree(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	subject, err := h.subjects.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	subtree, err := h.subjects.GetSubtree(*subject)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSubjectTreeResponse(subtree))
+}
+
+// loadSubject loads the subject named by the id parameter with its ancestors
+func (h *SubjectHandler) loadSubject(c *gin.Context) (*models.Subject, []models.Subject, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, errInvalidID
+	}
+
+	subject, err := h.subjects.GetByID(uint(id))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ancestors, err := h.subjects.GetAncestors(*subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	return subject, ancestors, nil
+}
+
+// CreateSubject godoc
+// @Summary Create new subject
+// @Description Create a new subject at the top level or under parent_id. Codes are unique across the whole tree. Requires the librarian or admin role.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param subject body dto.CreateSubjectRequest true "Subject object that needs to be added"
+// @Success 201 {object} dto.SubjectResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input or parent does not exist"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 409 {object} dto.ProblemResponse "A subject with this code already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects [post]
+func (h *SubjectHandler) CreateSubject(c *gin.Context) {
+	var req dto.CreateSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	parent, err := h.parent(req.ParentID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	parentPath := ""
+	if parent != nil {
+		parentPath = parent.Path
+	}
+
+	// Convert DTO to model
+	subject := dto.CreateSubjectRequestToModel(req)
+
+	if err := h.subjects.Create(&subject, parentPath); err != nil {
+		if apperr.From(err).Code == errSubjectExists.Code {
+			err = errSubjectExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToSubjectResponse(subject))
+}
+
+// parent loads the parent_id of a subject request, nil for the top level
+func (h *SubjectHandler) parent(id *uint) (*models.Subject, error) {
+	if id == nil {
+		return nil, nil
+	}
+	parent, err := h.subjects.GetByID(*id)
+	if err != nil {
+		field := apperr.FieldError{Field: "parent_id", Rule: "exists", Message: "is not an existing subject"}
+		return nil, replaceNotFound(err, apperr.Validation(errSubjectDoesNotExist.Code, errSubjectDoesNotExist.Message, field))
+	}
+	return parent, nil
+}
+
+// UpdateSubject godoc
+// @Summary Replace subject
+// @Description Replace the code and label of an existing subject. Move it to another parent with POST /api/v1/subjects/{id}/move. Requires the librarian or admin role.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Subject ID" minimum(1)
+// @Param If-Match header string false "ETag of the subject the change is based on"
+// @Param subject body dto.UpdateSubjectRequest true "The new state of the subject"
+// @Success 200 {object} dto.SubjectResponse
+// @Header 200 {string} ETag "ETag of the updated subject"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found"
+// @Failure 409 {object} dto.ProblemResponse "A subject with this code already exists or the subject was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The subject has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id} [put]
+func (h *SubjectHandler) UpdateSubject(c *gin.Context) {
+	subject, ancestors, err := h.loadSubject(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, subjectETag(*subject, ancestors)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveSubject(c, subject, ancestors, req)
+}
+
+// PatchSubject godoc
+// @Summary Update subject
+// @Description Change the code or label of a subject with a JSON Merge Patch or a JSON Patch. Requires the librarian or admin role.
+// @Tags subjects
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Subject ID" minimum(1)
+// @Param If-Match header string false "ETag of the subject the change is based on"
+// @Param subject body dto.UpdateSubjectRequest true "The fields to change"
+// @Success 200 {object} dto.SubjectResponse
+// @Header 200 {string} ETag "ETag of the updated subject"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting subject"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found"
+// @Failure 409 {object} dto.ProblemResponse "A subject with this code already exists, a test operation failed or the subject was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The subject has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id} [patch]
+func (h *SubjectHandler) PatchSubject(c *gin.Context) {
+	subject, ancestors, err := h.loadSubject(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, subjectETag(*subject, ancestors)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateSubjectRequest
+	if err := bindPatch(c, dto.ToUpdateSubjectRequest(*subject), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveSubject(c, subject, ancestors, req)
+}
+
+// saveSubject replaces subject with req and responds with the result
+func (h *SubjectHandler) saveSubject(c *gin.Context, subject *models.Subject, ancestors []models.Subject, req dto.UpdateSubjectRequest) {
+	// Update model from DTO
+	dto.UpdateSubjectModelFromRequest(subject, req)
+
+	if err := h.subjects.Update(subject); err != nil {
+		if apperr.From(err).Code == errSubjectExists.Code {
+			err = errSubjectExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", subjectETag(*subject, ancestors))
+	c.JSON(http.StatusOK, dto.ToSubjectResponse(*subject))
+}
+
+// MoveSubject godoc
+// @Summary Move subject
+// @Description Move a subject and all of its descendants under another parent, or to the top level when parent_id is null. Books filed under the moved subjects are then also found through their new ancestors. Requires the librarian or admin role.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Subject ID" minimum(1)
+// @Param If-Match header string false "ETag of the subject the move is based on"
+// @Param move body dto.MoveSubjectRequest true "The new parent of the subject"
+// @Success 200 {object} dto.SubjectDetailResponse
+// @Header 200 {string} ETag "ETag of the moved subject"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, parent does not exist or is the subject or one of its descendants"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found"
+// @Failure 409 {object} dto.ProblemResponse "The subject was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The subject has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id}/move [post]
+func (h *SubjectHandler) MoveSubject(c *gin.Context) {
+	subject, ancestors, err := h.loadSubject(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, subjectETag(*subject, ancestors)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.MoveSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	parent, err := h.parent(req.ParentID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if err := h.subjects.Move(subject, parent); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ancestors, err = h.subjects.GetAncestors(*subject)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", subjectETag(*subject, ancestors))
+	c.JSON(http.StatusOK, dto.ToSubjectDetailResponse(*subject, ancestors))
+}
+
+// DeleteSubject godoc
+// @Summary Delete subject
+// @Description Delete a subject without children and take it off its books, which are kept. Subjects are not soft deleted and cannot be restored. Requires the librarian or admin role.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Subject ID" minimum(1)
+// @Param If-Match header string false "ETag of the subject the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Subject not found, for conditional deletes"
+// @Failure 409 {object} dto.ProblemResponse "The subject has child subjects, listed as dependents"
+// @Failure 412 {object} dto.ProblemResponse "The subject has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/subjects/{id} [delete]
+func (h *SubjectHandler) DeleteSubject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current subject
+	if c.GetHeader("If-Match") != "" {
+		subject, ancestors, err := h.loadSubject(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, subjectETag(*subject, ancestors)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.subjects.Delete(uint(id)); err != nil {
+		// Child subjects are the only records that block a deletion
+		if apperr.From(err).Code == "subject_in_use" {
+			err = h.subjectHasChildren(uint(id))
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// subjectHasChildren builds the conflict for deleting a subject with children,
+// listing the first page of them
+func (h *SubjectHandler) subjectHasChildren(id uint) error {
+	children, _, err := h.subjects.GetChildren(&id, 1, 100)
+	if err != nil {
+		return err
+	}
+
+	dependents := make([]apperr.Dependent, len(children))
+	for i, child := range children {
+		dependents[i] = apperr.Dependent{Resource: "subject", ID: child.ID, Name: child.Label}
+	}
+	return apperr.Conflict(errSubjectHasChildren.Code, errSubjectHasChildren.Message, dependents...)
+}
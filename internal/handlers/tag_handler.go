@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errTagExists = apperr.Conflict("tag_exists", "A tag with this name already exists")
+
+// TagHandler serves the tag endpoints
+type TagHandler struct {
+	tags repository.TagRepository
+}
+
+// NewTagHandler creates a TagHandler backed by the given repository
+func NewTagHandler(tags repository.TagRepository) *TagHandler {
+	return &TagHandler{tags: tags}
+}
+
+// GetTags godoc
+// @Summary Get all tags
+// @Description Get a paginated list of tags ordered by name, with the number of books in each
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedTagsResponse
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	tags, totalCount, err := h.tags.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	counts, err := h.tags.CountBooks(ids)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	tagResponses := make([]dto.TagDetailResponse, len(tags))
+	for i, tag := range tags {
+		tagResponses[i] = dto.ToTagDetailResponse(tag, counts[tag.ID])
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedTagsResponse{
+		Data:       tagResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetTag godoc
+// @Summary Get tag by ID
+// @Description Get a tag by ID with its number of books. List the books with GET /api/v1/books?tag={id}.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID" minimum(1)
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} dto.TagDetailResponse
+// @Header 200 {string} ETag "Changes with the tag and its number of books"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 404 {object} dto.ProblemResponse "Tag not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	tag, bookCount, err := h.loadTag(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if notModified(c, tagETag(*tag, bookCount)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTagDetailResponse(*tag, bookCount))
+}
+
+// loadTag loads the tag named by the id parameter with its number of books
+func (h *TagHandler) loadTag(c *gin.Context) (*models.Tag, int64, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, 0, errInvalidID
+	}
+
+	tag, err := h.tags.GetByID(uint(id))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts, err := h.tags.CountBooks([]uint{tag.ID})
+	if err != nil {
+		return nil, 0, err
+	}
+	return tag, counts[tag.ID], nil
+}
+
+// CreateTag godoc
+// @Summary Create new tag
+// @Description Create a new subject tag. Names are unique regardless of case. Requires the librarian or admin role.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param tag body dto.CreateTagRequest true "Tag object that needs to be added"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid input"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 409 {object} dto.ProblemResponse "A tag with this name already exists"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Convert DTO to model
+	tag := dto.CreateTagRequestToModel(req)
+
+	if err := h.tags.Create(&tag); err != nil {
+		if apperr.From(err).Code == errTagExists.Code {
+			err = errTagExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToTagResponse(tag))
+}
+
+// UpdateTag godoc
+// @Summary Replace tag
+// @Description Replace an existing tag, which renames it. Requires the librarian or admin role.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Tag ID" minimum(1)
+// @Param If-Match header string false "ETag of the tag the change is based on"
+// @Param tag body dto.UpdateTagRequest true "The new state of the tag"
+// @Success 200 {object} dto.TagResponse
+// @Header 200 {string} ETag "ETag of the updated tag"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Tag not found"
+// @Failure 409 {object} dto.ProblemResponse "A tag with this name already exists or the tag was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The tag has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tag, bookCount, err := h.loadTag(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, tagETag(*tag, bookCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.saveTag(c, tag, bookCount, req)
+}
+
+// PatchTag godoc
+// @Summary Update tag
+// @Description Rename a tag with a JSON Merge Patch or a JSON Patch. Requires the librarian or admin role.
+// @Tags tags
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Tag ID" minimum(1)
+// @Param If-Match header string false "ETag of the tag the change is based on"
+// @Param tag body dto.UpdateTagRequest true "The fields to change"
+// @Success 200 {object} dto.TagResponse
+// @Header 200 {string} ETag "ETag of the updated tag"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format, patch or resulting tag"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Tag not found"
+// @Failure 409 {object} dto.ProblemResponse "A tag with this name already exists, a test operation failed or the tag was modified concurrently"
+// @Failure 412 {object} dto.ProblemResponse "The tag has changed since it was read"
+// @Failure 415 {object} dto.ProblemResponse "Unsupported patch format"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags/{id} [patch]
+func (h *TagHandler) PatchTag(c *gin.Context) {
+	tag, bookCount, err := h.loadTag(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := checkIfMatch(c, tagETag(*tag, bookCount)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := bindPatch(c, dto.ToUpdateTagRequest(*tag), &req); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	h.saveTag(c, tag, bookCount, req)
+}
+
+// saveTag replaces tag with req and responds with the result
+func (h *TagHandler) saveTag(c *gin.Context, tag *models.Tag, bookCount int64, req dto.UpdateTagRequest) {
+	// Update model from DTO
+	dto.UpdateTagModelFromRequest(tag, req)
+
+	if err := h.tags.Update(tag); err != nil {
+		if apperr.From(err).Code == errTagExists.Code {
+			err = errTagExists
+		}
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", tagETag(*tag, bookCount))
+	c.JSON(http.StatusOK, dto.ToTagResponse(*tag))
+}
+
+// DeleteTag godoc
+// @Summary Delete tag
+// @Description Delete a tag and take it off its books, which are kept. Unlike books, authors and reviews, tags are not soft deleted and cannot be restored. Requires the librarian or admin role.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Tag ID" minimum(1)
+// @Param If-Match header string false "ETag of the tag the deletion is based on"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "Tag not found, for conditional deletes"
+// @Failure 412 {object} dto.ProblemResponse "The tag has changed since it was read"
+// @Failure 428 {object} dto.ProblemResponse "If-Match is required"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	// Only conditional deletes need the current tag
+	if c.GetHeader("If-Match") != "" {
+		tag, bookCount, err := h.loadTag(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if err := checkIfMatch(c, tagETag(*tag, bookCount)); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if err := h.tags.Delete(uint(id)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errIncludeDeletedForbidden = apperr.Forbidden("insufficient_role", "Only admins can include deleted records")
+
+// TrashHandler serves the admin endpoint that permanently removes soft deleted records
+type TrashHandler struct {
+	trash     repository.TrashRepository
+	retention time.Duration
+}
+
+// NewTrashHandler creates a TrashHandler that by default purges records
+// deleted longer than retention ago
+func NewTrashHandler(trash repository.TrashRepository, retention time.Duration) *TrashHandler {
+	return &TrashHandler{trash: trash, retention: retention}
+}
+
+// Purge godoc
+// @Summary Purge deleted records
+// @Description Permanently remove books, reviews and authors that were deleted longer ago than the retention period. Authors that deleted books still refer to are kept until those books are purged. Nothing is removed if the purge fails.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param older_than_days query int false "Retention period in days, defaults to SOFT_DELETE_RETENTION_DAYS or 30" minimum(0)
+// @Success 200 {object} dto.PurgeResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid older_than_days"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/admin/purge [post]
+func (h *TrashHandler) Purge(c *gin.Context) {
+	retention := h.retention
+	if value := c.Query("older_than_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			abortWithError(c, invalidQuery(fmt.Errorf("invalid older_than_days %q", value)))
+			return
+		}
+		retention = time.Duration(days) * 24 * time.Hour
+	}
+	before := time.Now().Add(-retention)
+
+	purged, err := h.trash.Purge(before)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PurgeResponse{
+		DeletedBefore: before,
+		Books:         purged.Books,
+		Reviews:       purged.Reviews,
+		Authors:       purged.Authors,
+	})
+}
+
+// parseIncludeDeleted reads the include_deleted parameter of list endpoints,
+// which only admin users may set
+func parseIncludeDeleted(c *gin.Context) (bool, error) {
+	value := c.Query("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, invalidQuery(fmt.Errorf("invalid include_deleted %q, expected true or false", value))
+	}
+	if !includeDeleted {
+		return false, nil
+	}
+
+	identity, ok := middleware.CurrentIdentity(c)
+	if !ok {
+		return false, apperr.Unauthorized("authentication_required", "Authentication required")
+	}
+	if identity.IsAPIKey() || identity.Role != models.RoleAdmin {
+		return false, errIncludeDeletedForbidden
+	}
+	return true, nil
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"go-rest-api/internal/dto"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler serves the admin-only user management endpoints
+type UserHandler struct {
+	users repository.UserRepository
+}
+
+// NewUserHandler creates a UserHandler backed by the given repository
+func NewUserHandler(users repository.UserRepository) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+// GetUsers godoc
+// @Summary Get all users
+// @Description Get a paginated list of user accounts with their roles
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param page_size query int false "Number of items per page" minimum(1) maximum(100) default(10)
+// @Success 200 {object} dto.PaginatedUsersResponse
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/users [get]
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize := parsePageSize(c)
+
+	users, totalCount, err := h.users.GetAll(page, pageSize)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// Convert models to DTOs
+	userResponses := make([]dto.UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = dto.ToUserResponse(user)
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedUsersResponse{
+		Data:       userResponses,
+		Total:      totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((totalCount + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// UpdateUserRole godoc
+// @Summary Change a user's role
+// @Description Set the role of a user account. The user's tokens carry the new role after their next refresh.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID" minimum(1)
+// @Param role body dto.UpdateUserRoleRequest true "New role"
+// @Success 200 {object} dto.UserResponse
+// @Failure 400 {object} dto.ProblemResponse "Invalid ID format or request body"
+// @Failure 401 {object} dto.ProblemResponse "Authentication required"
+// @Failure 403 {object} dto.ProblemResponse "Insufficient permissions"
+// @Failure 404 {object} dto.ProblemResponse "User not found"
+// @Failure 500 {object} dto.ProblemResponse "Server error"
+// @Router /api/v1/users/{id}/role [put]
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		abortWithError(c, errInvalidID)
+		return
+	}
+
+	user, err := h.users.GetByID(uint(id))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	user.Role = req.Role
+
+	if err := h.users.Update(user); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToUserResponse(*user))
+}
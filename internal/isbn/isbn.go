@@ -0,0 +1,83 @@
+// Package isbn validates ISBN-10 and ISBN-13 numbers and normalizes them to ISBN-13.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for values that are not a valid ISBN-10 or ISBN-13
+var ErrInvalid = errors.New("invalid ISBN")
+
+// Valid reports whether value is an ISBN-10 or ISBN-13 with a correct check digit.
+// Hyphens and spaces are ignored.
+func Valid(value string) bool {
+	_, err := Normalize(value)
+	return err == nil
+}
+
+// Normalize returns the bare 13 digit form of an ISBN-10 or ISBN-13, converting
+// ISBN-10s to their 978 prefixed ISBN-13. Hyphens and spaces are ignored.
+func Normalize(value string) (string, error) {
+	digits := compact(value)
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalid
+		}
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), nil
+	case 13:
+		if !allDigits(digits) || isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalid
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalid
+	}
+}
+
+// compact strips separators and upper cases the X check digit of ISBN-10s
+func compact(value string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(value)))
+}
+
+// validISBN10 checks the mod 11 checksum, where X stands for 10 in the last position
+func validISBN10(digits string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch {
+		case digits[i] >= '0' && digits[i] <= '9':
+			d = int(digits[i] - '0')
+		case digits[i] == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an ISBN-13
+func isbn13CheckDigit(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(first12[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
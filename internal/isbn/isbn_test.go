@@ -0,0 +1,59 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string // empty for invalid values
+	}{
+		{name: "isbn-13", value: "9780306406157", want: "9780306406157"},
+		{name: "isbn-10", value: "0306406152", want: "9780306406157"},
+		{name: "isbn-13 with hyphens", value: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "isbn-10 with hyphens", value: "0-306-40615-2", want: "9780306406157"},
+		{name: "spaces", value: " 978 0 306 40615 7 ", want: "9780306406157"},
+		{name: "hyphens and spaces", value: "0 - 7475 - 3269 - 9", want: "9780747532699"},
+		{name: "x check digit", value: "0-8044-2957-X", want: "9780804429573"},
+		{name: "lower case x check digit", value: "080442957x", want: "9780804429573"},
+		{name: "979 prefix", value: "979-10-90636-07-1", want: "9791090636071"},
+		// 979 prefixed ISBN-13s have no ISBN-10 form: the ISBN-10 made of the
+		// same digits is another book, under the 978 prefix
+		{name: "isbn-10 of the digits of a 979 prefix", value: "1-09-063607-5", want: "9781090636072"},
+		{name: "isbn-13 with a wrong check digit", value: "9780306406158"},
+		{name: "isbn-10 with a wrong check digit", value: "0306406153"},
+		{name: "isbn-10 with x for a digit check digit", value: "030640615X"},
+		{name: "x before the check digit", value: "03064061X2"},
+		{name: "isbn-13 with x check digit", value: "978030640615X"},
+		{name: "979 prefix with a wrong check digit", value: "9791090636072"},
+		{name: "letters", value: "978O306406157"},
+		{name: "other separators", value: "978.0.306.40615.7"},
+		{name: "too short", value: "030640615"},
+		{name: "too long", value: "97803064061570"},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalid) {
+					t.Errorf("Normalize(%q) = %q, %v, want ErrInvalid", tt.value, got, err)
+				}
+				if Valid(tt.value) {
+					t.Errorf("Valid(%q) = true", tt.value)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+			if !Valid(tt.value) {
+				t.Errorf("Valid(%q) = false", tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/repository"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// identityKey is the gin context key holding the authenticated auth.Identity
+const identityKey = "identity"
+
+// apiKeyHeader carries the API key of machine clients
+const apiKeyHeader = "X-API-Key"
+
+// lastUsedResolution limits how often a key's last_used_at is written
+const lastUsedResolution = time.Minute
+
+// Authenticate reads an API key from the X-API-Key header or a Bearer access
+// token from the Authorization header. Read requests pass through anonymously
+// when there is neither; write requests are rejected with 401 unless a valid
+// key or token is present.
+func Authenticate(tokens *auth.TokenService, apiKeys repository.APIKeyRepository) gin.HandlerFunc {
+	return authenticate(tokens, apiKeys, isReadOnly)
+}
+
+// AuthenticateReads is Authenticate for a group of routes that only read,
+// whatever their method, such as lookups that use POST to carry a request
+// body. Requests without a key or token pass through anonymously.
+func AuthenticateReads(tokens *auth.TokenService, apiKeys repository.APIKeyRepository) gin.HandlerFunc {
+	return authenticate(tokens, apiKeys, func(string) bool { return true })
+}
+
+func authenticate(tokens *auth.TokenService, apiKeys repository.APIKeyRepository, readOnly func(method string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(apiKeyHeader); key != "" {
+			authenticateAPIKey(c, apiKeys, key)
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			if readOnly(c.Request.Method) {
+				c.Next()
+				return
+			}
+			unauthorized(c, apperr.Unauthorized("authentication_required", "Authentication required"))
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			unauthorized(c, apperr.Unauthorized("invalid_authorization_header", "Authorization header must use the Bearer scheme"))
+			return
+		}
+
+		identity, err := tokens.Parse(strings.TrimSpace(token), auth.TokenTypeAccess)
+		if err != nil {
+			unauthorized(c, apperr.Unauthorized("invalid_token", "Invalid or expired token"))
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// authenticateAPIKey looks up key by its hash and records when it was used
+func authenticateAPIKey(c *gin.Context, apiKeys repository.APIKeyRepository, key string) {
+	apiKey, err := apiKeys.GetByHash(auth.HashAPIKey(strings.TrimSpace(key)))
+	if apperr.KindOf(err) == apperr.KindNotFound {
+		unauthorized(c, apperr.Unauthorized("invalid_api_key", "Invalid API key"))
+		return
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := apiKeys.TouchLastUsed(apiKey.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %d: %v", apiKey.ID, err)
+		}
+	}
+
+	c.Set(identityKey, auth.Identity{APIKeyID: apiKey.ID, Scopes: strings.Fields(apiKey.Scopes)})
+	c.Next()
+}
+
+// Authorize guards a route with an API key scope and, for users, a set of roles.
+// API keys need scope; users need one of roles, or nothing when roles is empty.
+// Anonymous callers are only let through when no roles are required, which
+// Authenticate limits to read requests. It must run after Authenticate.
+func Authorize(scope string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := CurrentIdentity(c)
+		switch {
+		case !ok:
+			if len(roles) > 0 {
+				unauthorized(c, apperr.Unauthorized("authentication_required", "Authentication required"))
+				return
+			}
+		case identity.IsAPIKey():
+			if scope == "" || !identity.HasScope(scope) {
+				abortWithError(c, apperr.Forbidden("insufficient_scope", "API key is missing the required scope"))
+				return
+			}
+		case len(roles) > 0 && !slices.Contains(roles, identity.Role):
+			abortWithError(c, apperr.Forbidden("insufficient_role", "Insufficient permissions"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope lets anonymous reads and any user through but requires scope from API keys
+func RequireScope(scope string) gin.HandlerFunc {
+	return Authorize(scope)
+}
+
+// RequireRole only lets users with one of roles through, API keys are refused.
+// It must run after Authenticate.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return Authorize("", roles...)
+}
+
+// CurrentIdentity returns the authenticated caller, if any
+func CurrentIdentity(c *gin.Context) (auth.Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return auth.Identity{}, false
+	}
+	identity, ok := value.(auth.Identity)
+	return identity, ok
+}
+
+// CurrentUserID returns the ID of the authenticated user, if any. Callers
+// authenticated with an API key have no user.
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	identity, ok := CurrentIdentity(c)
+	if !ok || identity.IsAPIKey() {
+		return 0, false
+	}
+	return identity.UserID, true
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func unauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	abortWithError(c, err)
+}
+
+// abortWithError stops the chain and leaves err for ErrorHandler to render
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newAuthRouter serves GET and POST /things behind Authenticate and POST
+// /things/lookup behind AuthenticateReads, answering with the caller's user ID
+func newAuthRouter(tokens *auth.TokenService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	apiKeys := repository.NewMemoryStore().APIKeys()
+	whoami := func(c *gin.Context) {
+		identity, _ := CurrentIdentity(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": identity.UserID})
+	}
+	api := r.Group("", Authenticate(tokens, apiKeys))
+	api.GET("/things", whoami)
+	api.POST("/things", whoami)
+	reads := r.Group("", AuthenticateReads(tokens, apiKeys))
+	reads.POST("/things/lookup", whoami)
+	return r
+}
+
+func TestAuthenticate(t *testing.T) {
+	tokens := auth.NewTokenService([]byte("test secret"), time.Minute, time.Hour)
+	pair, err := tokens.Issue(auth.Identity{UserID: 7, Role: "user"})
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	expired, err := auth.NewTokenService([]byte("test secret"), -time.Minute, time.Hour).Issue(auth.Identity{UserID: 7, Role: "user"})
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	forged, err := auth.NewTokenService([]byte("other secret"), time.Minute, time.Hour).Issue(auth.Identity{UserID: 7, Role: "admin"})
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{name: "anonymous read", method: http.MethodGet, path: "/things", wantStatus: http.StatusOK},
+		{name: "anonymous read-only post", method: http.MethodPost, path: "/things/lookup", wantStatus: http.StatusOK},
+		{name: "anonymous write", method: http.MethodPost, path: "/things", wantStatus: http.StatusUnauthorized, wantCode: "authentication_required"},
+		{name: "access token", method: http.MethodPost, path: "/things", authorization: "Bearer " + pair.AccessToken, wantStatus: http.StatusOK},
+		{name: "basic scheme", method: http.MethodPost, path: "/things", authorization: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized, wantCode: "invalid_authorization_header"},
+		{name: "refresh token", method: http.MethodPost, path: "/things", authorization: "Bearer " + pair.RefreshToken, wantStatus: http.StatusUnauthorized, wantCode: "invalid_token"},
+		{name: "expired token", method: http.MethodPost, path: "/things", authorization: "Bearer " + expired.AccessToken, wantStatus: http.StatusUnauthorized, wantCode: "invalid_token"},
+		{name: "token of another secret", method: http.MethodPost, path: "/things", authorization: "Bearer " + forged.AccessToken, wantStatus: http.StatusUnauthorized, wantCode: "invalid_token"},
+		{name: "invalid token on a read", method: http.MethodGet, path: "/things", authorization: "Bearer garbage", wantStatus: http.StatusUnauthorized, wantCode: "invalid_token"},
+		{name: "invalid token on a read-only post", method: http.MethodPost, path: "/things/lookup", authorization: "Bearer garbage", wantStatus: http.StatusUnauthorized, wantCode: "invalid_token"},
+		{name: "access token on a read-only post", method: http.MethodPost, path: "/things/lookup", authorization: "Bearer " + pair.AccessToken, wantStatus: http.StatusOK},
+	}
+
+	router := newAuthRouter(tokens)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			if !strings.Contains(w.Body.String(), `"code":"`+tt.wantCode+`"`) {
+				t.Errorf("body = %s, want code %s", w.Body, tt.wantCode)
+			}
+			if w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate header")
+			}
+		})
+	}
+}
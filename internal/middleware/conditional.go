@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"go-rest-api/internal/apperr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch rejects requests without an If-Match header with 428, so that
+// clients cannot overwrite changes they have not seen. When required is false
+// every request is let through and If-Match is only honored when sent.
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader("If-Match") == "" {
+			abortWithError(c, apperr.PreconditionRequired("if_match_required", "This request requires an If-Match header with the ETag of the resource"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/dto"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler renders the last error recorded with c.Error as an RFC 7807
+// application/problem+json response. Internal errors are logged with the
+// request ID and replaced by a generic message.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := apperr.From(c.Errors.Last().Err)
+		requestID := CurrentRequestID(c)
+		if err.Kind == apperr.KindInternal {
+			log.Printf("[%s] %s %s: %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+		}
+
+		status := err.Kind.Status()
+		problem := dto.ProblemResponse{
+			Type:      "about:blank",
+			Title:     http.StatusText(status),
+			Status:    status,
+			Detail:    err.Message,
+			Instance:  c.Request.URL.Path,
+			Code:      err.Code,
+			RequestID: requestID,
+		}
+		for _, field := range err.Fields {
+			problem.Errors = append(problem.Errors, dto.FieldErrorResponse{Field: field.Field, Rule: field.Rule, Message: field.Message})
+		}
+		for _, dependent := range err.Dependents {
+			problem.Dependents = append(problem.Dependents, dto.DependentResponse{Resource: dependent.Resource, ID: dependent.ID, Name: dependent.Name})
+		}
+
+		c.Header("Content-Type", "application/problem+json")
+		c.JSON(status, problem)
+	}
+}
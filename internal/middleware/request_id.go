@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDHeader carries the request ID in both directions
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID
+const requestIDKey = "requestID"
+
+// validRequestID limits client supplied IDs to something safe to log and echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID assigns every request an ID, reusing a well-formed X-Request-ID
+// header from the client, and echoes it in the response header
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// CurrentRequestID returns the ID assigned by RequestID, if any
+func CurrentRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
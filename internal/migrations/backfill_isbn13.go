@@ -0,0 +1,127 @@
+package migrations
+
+import (
+	"fmt"
+	"go-rest-api/internal/isbn"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// isbnRow is a book or edition without a normalized ISBN-13
+type isbnRow struct {
+	ID     uint
+	BookID uint
+	ISBN   string
+	Live   bool
+}
+
+// backfillISBN13 normalizes the ISBNs of books and editions that have no
+// isbn13, bumping their versions. Rows whose ISBN fails the checksum, or
+// would collide with the ISBN of another live book under the unique indexes,
+// keep a NULL isbn13 and are logged, so that they can be fixed afterwards
+// without holding up the migration.
+func backfillISBN13(tx *gorm.DB) error {
+	var problems []string
+	// Books reported once are not reported again for their first edition
+	reported := make(map[uint]string)
+
+	books, err := missingISBN13(tx, "books", "id AS book_id")
+	if err != nil {
+		return err
+	}
+	taken, err := takenISBN13s(tx, "books", "id")
+	if err != nil {
+		return err
+	}
+	bookISBN13s := make(map[uint]string)
+	for _, book := range books {
+		isbn13, problem := checkISBN(book, taken)
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("book %d: %s", book.ID, problem))
+			reported[book.ID] = book.ISBN
+			continue
+		}
+		bookISBN13s[book.ID] = isbn13
+	}
+
+	editions, err := missingISBN13(tx, "editions", "book_id")
+	if err != nil {
+		return err
+	}
+	taken, err = takenISBN13s(tx, "editions", "book_id")
+	if err != nil {
+		return err
+	}
+	editionISBN13s := make(map[uint]string)
+	for _, edition := range editions {
+		isbn13, problem := checkISBN(edition, taken)
+		if problem != "" {
+			if isbn, ok := reported[edition.BookID]; !ok || isbn != edition.ISBN {
+				problems = append(problems, fmt.Sprintf("edition %d of book %d: %s", edition.ID, edition.BookID, problem))
+			}
+			continue
+		}
+		editionISBN13s[edition.ID] = isbn13
+	}
+
+	if len(problems) > 0 {
+		logf("%d ISBN(s) cannot be normalized and are left without an ISBN-13 until they are updated:\n%s",
+			len(problems), strings.Join(problems, "\n"))
+	}
+
+	for table, isbn13s := range map[string]map[uint]string{"books": bookISBN13s, "editions": editionISBN13s} {
+		for id, isbn13 := range isbn13s {
+			err := tx.Table(table).Where("id = ?", id).
+				Updates(map[string]any{"isbn13": isbn13, "version": gorm.Expr("version + 1")}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// missingISBN13 returns the rows of a table with an ISBN but no isbn13,
+// ordered by ID. Rows without any ISBN have nothing to normalize.
+func missingISBN13(tx *gorm.DB, table, bookID string) ([]isbnRow, error) {
+	var rows []isbnRow
+	err := tx.Table(table).
+		Select("id, " + bookID + ", COALESCE(isbn, '') AS isbn, deleted_at IS NULL AS live").
+		Where("isbn13 IS NULL AND COALESCE(isbn, '') <> ''").Order("id").Scan(&rows).Error
+	return rows, err
+}
+
+// takenISBN13s maps the ISBN-13s of the live rows of a table to their book
+func takenISBN13s(tx *gorm.DB, table, bookID string) (map[string]uint, error) {
+	var rows []struct {
+		ISBN13 string `gorm:"column:isbn13"`
+		BookID uint
+	}
+	err := tx.Table(table).Select("isbn13, " + bookID + " AS book_id").
+		Where("isbn13 IS NOT NULL AND deleted_at IS NULL").Scan(&rows).Error
+	taken := make(map[string]uint, len(rows))
+	for _, row := range rows {
+		taken[row.ISBN13] = row.BookID
+	}
+	return taken, err
+}
+
+// checkISBN normalizes the ISBN of a row, reserving it in taken for live rows.
+// It describes the problem when the ISBN is invalid or another book has it.
+func checkISBN(row isbnRow, taken map[string]uint) (string, string) {
+	isbn13, err := isbn.Normalize(row.ISBN)
+	if err != nil {
+		return "", fmt.Sprintf("%q is not a valid ISBN-10 or ISBN-13", row.ISBN)
+	}
+	if !row.Live {
+		// Deleted rows are not covered by the unique indexes, restoring them
+		// fails while another book has the ISBN
+		return isbn13, ""
+	}
+	if other, ok := taken[isbn13]; ok {
+		return "", fmt.Sprintf("%q is the ISBN of book %d", row.ISBN, other)
+	}
+	taken[isbn13] = row.BookID
+	return isbn13, ""
+}
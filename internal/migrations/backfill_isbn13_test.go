@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"fmt"
+	"go-rest-api/internal/repository"
+	"log"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// legacyBook is a book saved before migration 0006 normalized ISBNs
+type legacyBook struct {
+	isbn    string
+	deleted bool
+}
+
+// migrateWithLegacyBooks migrates an in-memory SQLite database around 0012,
+// saving the books the way the server did before 0006
+func migrateWithLegacyBooks(t *testing.T, books []legacyBook) (*gorm.DB, error) {
+	t.Helper()
+	return migrateAround(t, 12, func(db *gorm.DB) {
+		if err := db.Exec("INSERT INTO authors (name) VALUES ('Author')").Error; err != nil {
+			t.Fatalf("create author: %v", err)
+		}
+		for _, book := range books {
+			deletedAt := "NULL"
+			if book.deleted {
+				deletedAt = "CURRENT_TIMESTAMP"
+			}
+			err := db.Exec("INSERT INTO books (title, author_id, isbn, deleted_at) VALUES ('Book', 1, ?, "+deletedAt+")", book.isbn).Error
+			if err != nil {
+				t.Fatalf("create book %q: %v", book.isbn, err)
+			}
+		}
+	})
+}
+
+// isbn13s describes the isbn13 and version of the rows of a table, by ID
+func isbn13s(t *testing.T, db *gorm.DB, table string) []string {
+	t.Helper()
+	var rows []struct {
+		ISBN13  *string `gorm:"column:isbn13"`
+		Version int
+	}
+	if err := db.Table(table).Select("isbn13, version").Order("id").Scan(&rows).Error; err != nil {
+		t.Fatalf("read %s: %v", table, err)
+	}
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		isbn13 := "<nil>"
+		if row.ISBN13 != nil {
+			isbn13 = *row.ISBN13
+		}
+		got[i] = fmt.Sprintf("%s v%d", isbn13, row.Version)
+	}
+	return got
+}
+
+func TestBackfillISBN13(t *testing.T) {
+	db, err := migrateWithLegacyBooks(t, []legacyBook{
+		{isbn: "0-7475-3269-9"},
+		{isbn: "978 0 306 40615 7"},
+		{isbn: "0-19-852663-6", deleted: true},
+		// Deleted books are not covered by the unique index
+		{isbn: "019852663-6"},
+		{isbn: ""},
+	})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	want := []string{"9780747532699 v2", "9780306406157 v2", "9780198526636 v2", "9780198526636 v2", "<nil> v1"}
+	for _, table := range []string{"books", "editions"} {
+		got := isbn13s(t, db, table)
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Errorf("%s isbn13s = %v, want %v", table, got, want)
+		}
+	}
+
+	// Legacy books are found by their ISBN-13, in one lookup or in bulk
+	books := repository.NewBookRepository(db)
+	book, err := books.GetByISBN("9780747532699")
+	if err != nil || book.ID != 1 {
+		t.Errorf("GetByISBN = %v, %v, want book 1", book, err)
+	}
+	found, err := books.GetByISBNs([]string{"9780306406157", "9780198526636"})
+	if err != nil || len(found) != 2 {
+		t.Errorf("GetByISBNs = %d books, %v, want 2", len(found), err)
+	}
+}
+
+func TestBackfillISBN13Skips(t *testing.T) {
+	tests := []struct {
+		name  string
+		books []legacyBook
+		want  []string
+		log   []string
+	}{
+		{
+			name:  "invalid checksum",
+			books: []legacyBook{{isbn: "0-7475-3269-9"}, {isbn: "0-7475-3269-8"}},
+			want:  []string{"9780747532699 v2", "<nil> v1"},
+			log:   []string{"1 ISBN(s)", `book 2: "0-7475-3269-8" is not a valid ISBN-10 or ISBN-13`},
+		},
+		{
+			name:  "collision",
+			books: []legacyBook{{isbn: "0-7475-3269-9"}, {isbn: "978-0-7475-3269-9"}},
+			want:  []string{"9780747532699 v2", "<nil> v1"},
+			log:   []string{"1 ISBN(s)", `book 2: "978-0-7475-3269-9" is the ISBN of book 1`},
+		},
+		{
+			name:  "several",
+			books: []legacyBook{{isbn: "not an isbn"}, {isbn: "0306406152"}, {isbn: "9780306406157"}},
+			want:  []string{"<nil> v1", "9780306406157 v2", "<nil> v1"},
+			log: []string{
+				"2 ISBN(s)",
+				`book 1: "not an isbn" is not a valid ISBN-10 or ISBN-13`,
+				`book 3: "9780306406157" is the ISBN of book 2`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []string
+			logf = func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+			t.Cleanup(func() { logf = log.Printf })
+
+			// The migration goes ahead without the ISBNs it cannot normalize
+			db, err := migrateWithLegacyBooks(t, tt.books)
+			if err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+			for _, table := range []string{"books", "editions"} {
+				got := isbn13s(t, db, table)
+				if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+					t.Errorf("%s isbn13s = %v, want %v", table, got, tt.want)
+				}
+			}
+
+			if len(logged) != 1 {
+				t.Fatalf("logged %q, want one report", logged)
+			}
+			for _, want := range tt.log {
+				if !strings.Contains(logged[0], want) {
+					t.Errorf("report %q does not contain %q", logged[0], want)
+				}
+			}
+			// First editions are reported with their book only
+			if strings.Contains(logged[0], "edition") {
+				t.Errorf("report %q lists editions", logged[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var files embed.FS
+
+// Migration files are named <version>_<name>.up.sql and <version>_<name>.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Step runs after Up in the same transaction, for data changes that SQL
+	// cannot express on every dialect or that are logged. Down does not undo it.
+	Step func(tx *gorm.DB) error
+}
+
+// steps holds the Go steps of migrations by version
+var steps = map[int]func(tx *gorm.DB) error{
+	8:  deleteOrphanedBooks,
+	15: backfillISBN13,
+}
+
+// logf reports the records that steps change or leave alone, tests replace it
+var logf = log.Printf
+
+// Status reports whether a migration has been applied
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the schema_migrations table
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and rolls back the migrations for one database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations matching the dialect of db
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load reads the embedded migrations of a dialect, ordered by version
+func load(dialect string) ([]Migration, error) {
+	dir := path.Join("sql", dialect)
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2], Step: steps[version]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// applied returns the applied migrations keyed by version
+func (m *Migrator) applied() (map[int]schemaMigration, error) {
+	createTable := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`
+	if err := m.db.Exec(createTable).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var rows []schemaMigration
+	if err := m.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Up applies every pending migration in order and returns the ones it ran
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			if migration.Step != nil {
+				if err := migration.Step(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		ran = append(ran, migration)
+	}
+	return ran, nil
+}
+
+// Down rolls back the most recently applied migration. It returns nil if nothing is applied.
+func (m *Migrator) Down() (*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// Status lists every known migration and when it was applied
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the number of migrations that have not been applied yet
+func (m *Migrator) Pending() (int, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// migrateAround migrates an in-memory SQLite database up to version, lets
+// seed save data the way the server did then, runs the other migrations and
+// returns the database and the error of the last step
+func migrateAround(t *testing.T, version int, seed func(db *gorm.DB)) (*gorm.DB, error) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:?_foreign_keys=1"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Every connection to ":memory:" opens its own database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("configure sqlite: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	all, err := load(db.Dialector.Name())
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	var before []Migration
+	for _, migration := range all {
+		if migration.Version <= version {
+			before = append(before, migration)
+		}
+	}
+	if _, err := (&Migrator{db: db, migrations: before}).Up(); err != nil {
+		t.Fatalf("migrate to %04d: %v", version, err)
+	}
+
+	seed(db)
+	_, err = (&Migrator{db: db, migrations: all}).Up()
+	return db, err
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// deleteOrphanedBooks soft deletes the live books of deleted authors, with
+// their live reviews, as if the authors had been deleted with cascade=true.
+// They take the deletion time of their author, so that restoring a book
+// brings its reviews back, and are logged, so that they can be restored
+// after their author.
+func deleteOrphanedBooks(tx *gorm.DB) error {
+	var orphans []struct {
+		ID       uint
+		AuthorID uint
+	}
+	err := tx.Table("books").Select("books.id, books.author_id").
+		Joins("JOIN authors ON authors.id = books.author_id").
+		Where("books.deleted_at IS NULL AND authors.deleted_at IS NOT NULL").
+		Order("books.id").Scan(&orphans).Error
+	if err != nil || len(orphans) == 0 {
+		return err
+	}
+
+	ids := make([]uint, len(orphans))
+	books := make([]string, len(orphans))
+	for i, orphan := range orphans {
+		ids[i] = orphan.ID
+		books[i] = fmt.Sprintf("book %d of author %d", orphan.ID, orphan.AuthorID)
+	}
+	err = tx.Exec(`UPDATE reviews SET deleted_at = (
+		SELECT authors.deleted_at FROM books JOIN authors ON authors.id = books.author_id
+		WHERE books.id = reviews.book_id
+	) WHERE deleted_at IS NULL AND book_id IN ?`, ids).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Exec(`UPDATE books SET deleted_at = (
+		SELECT authors.deleted_at FROM authors WHERE authors.id = books.author_id
+	) WHERE id IN ?`, ids).Error
+	if err != nil {
+		return err
+	}
+
+	logf("%d book(s) of deleted authors were deleted with their reviews, restore their author before them:\n%s",
+		len(orphans), strings.Join(books, "\n"))
+	return nil
+}
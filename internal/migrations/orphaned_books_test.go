@@ -0,0 +1,90 @@
+package migrations
+
+import (
+	"fmt"
+	"go-rest-api/internal/repository"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deletedAt returns when the rows of a table were deleted, by ID
+func deletedAt(t *testing.T, db *gorm.DB, table string) map[uint]*time.Time {
+	t.Helper()
+	var rows []struct {
+		ID        uint
+		DeletedAt *time.Time
+	}
+	if err := db.Table(table).Select("id, deleted_at").Scan(&rows).Error; err != nil {
+		t.Fatalf("read %s: %v", table, err)
+	}
+	got := make(map[uint]*time.Time, len(rows))
+	for _, row := range rows {
+		got[row.ID] = row.DeletedAt
+	}
+	return got
+}
+
+func TestDeleteOrphanedBooks(t *testing.T) {
+	var logged []string
+	logf = func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+	t.Cleanup(func() { logf = log.Printf })
+
+	authorDeleted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	earlier := authorDeleted.Add(-24 * time.Hour)
+	db, err := migrateAround(t, 7, func(db *gorm.DB) {
+		for _, statement := range []struct {
+			sql  string
+			args []any
+		}{
+			{"INSERT INTO authors (id, name) VALUES (1, 'Kept')", nil},
+			{"INSERT INTO authors (id, name, deleted_at) VALUES (2, 'Deleted', ?)", []any{authorDeleted}},
+			{"INSERT INTO books (id, title, author_id) VALUES (1, 'Kept', 1)", nil},
+			{"INSERT INTO books (id, title, author_id) VALUES (2, 'Orphan', 2)", nil},
+			{"INSERT INTO books (id, title, author_id, deleted_at) VALUES (3, 'Deleted', 2, ?)", []any{earlier}},
+			{"INSERT INTO reviews (id, book_id, rating) VALUES (1, 2, 5)", nil},
+			{"INSERT INTO reviews (id, book_id, rating, deleted_at) VALUES (2, 2, 1, ?)", []any{earlier}},
+			{"INSERT INTO reviews (id, book_id, rating) VALUES (3, 1, 4)", nil},
+		} {
+			if err := db.Exec(statement.sql, statement.args...).Error; err != nil {
+				t.Fatalf("%s: %v", statement.sql, err)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// The author stays deleted, the orphan and its live review go with it
+	want := map[string]map[uint]*time.Time{
+		"authors": {1: nil, 2: &authorDeleted},
+		"books":   {1: nil, 2: &authorDeleted, 3: &earlier},
+		"reviews": {1: &authorDeleted, 2: &earlier, 3: nil},
+	}
+	for table, wantDeleted := range want {
+		got := deletedAt(t, db, table)
+		for id, want := range wantDeleted {
+			if (got[id] == nil) != (want == nil) || got[id] != nil && !got[id].Equal(*want) {
+				t.Errorf("%s %d deleted_at = %v, want %v", table, id, got[id], want)
+			}
+		}
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "book 2 of author 2") || strings.Contains(logged[0], "book 3") {
+		t.Errorf("logged %q, want book 2 of author 2 only", logged)
+	}
+
+	// The book is restored with the review deleted with it after its author
+	if err := repository.NewAuthorRepository(db).Restore(2); err != nil {
+		t.Fatalf("restore author: %v", err)
+	}
+	if err := repository.NewBookRepository(db).Restore(2); err != nil {
+		t.Fatalf("restore book: %v", err)
+	}
+	reviews, err := repository.NewReviewRepository(db).GetByBookID(2, false)
+	if err != nil || len(reviews) != 1 || reviews[0].ID != 1 {
+		t.Errorf("reviews of the restored book = %v, %v, want review 1", reviews, err)
+	}
+}
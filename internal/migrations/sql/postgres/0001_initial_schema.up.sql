@@ -0,0 +1,40 @@
+-- Initial schema, equivalent to what AutoMigrate created for Author, Book and Review.
+-- IF NOT EXISTS lets databases created by AutoMigrate adopt the migration history.
+
+CREATE TABLE IF NOT EXISTS authors (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name       TEXT,
+    biography  TEXT,
+    birth_date TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_authors_deleted_at ON authors (deleted_at);
+
+CREATE TABLE IF NOT EXISTS books (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    deleted_at       TIMESTAMPTZ,
+    title            TEXT,
+    author_id        BIGINT,
+    isbn             TEXT,
+    publication_year BIGINT,
+    description      TEXT,
+    CONSTRAINT fk_authors_books FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    rating      BIGINT,
+    comment     TEXT,
+    date_posted TEXT,
+    book_id     BIGINT,
+    CONSTRAINT fk_books_reviews FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews (deleted_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_authors_search_vector;
+ALTER TABLE authors DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,13 @@
+-- Full-text search vectors for GET /api/v1/search.
+-- Book titles and descriptions are stemmed in English, author names are not.
+
+ALTER TABLE books ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX idx_books_search_vector ON books USING GIN (search_vector);
+
+ALTER TABLE authors ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(name, ''))
+) STORED;
+CREATE INDEX idx_authors_search_vector ON authors USING GIN (search_vector);
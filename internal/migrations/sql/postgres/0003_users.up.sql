@@ -0,0 +1,11 @@
+CREATE TABLE users (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ,
+    email         TEXT NOT NULL,
+    name          TEXT,
+    password_hash TEXT NOT NULL
+);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reviews_user_id;
+ALTER TABLE reviews DROP COLUMN IF EXISTS user_id;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
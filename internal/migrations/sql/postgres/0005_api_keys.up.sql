@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ,
+    name          TEXT NOT NULL,
+    prefix        TEXT NOT NULL,
+    key_hash      TEXT NOT NULL,
+    scopes        TEXT NOT NULL,
+    created_by_id BIGINT CONSTRAINT fk_users_api_keys REFERENCES users (id),
+    last_used_at  TIMESTAMPTZ
+);
+CREATE INDEX idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
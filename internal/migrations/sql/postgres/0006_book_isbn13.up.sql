@@ -0,0 +1,5 @@
+-- Normalized ISBN-13 of each book. 0015 backfills the books saved before this
+-- migration, those whose ISBN cannot be normalized keep NULL until it is next
+-- updated, so they are not covered by the unique index.
+ALTER TABLE books ADD COLUMN isbn13 TEXT;
+CREATE UNIQUE INDEX idx_books_isbn13 ON books (isbn13) WHERE deleted_at IS NULL;
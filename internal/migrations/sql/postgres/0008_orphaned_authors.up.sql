@@ -0,0 +1,4 @@
+-- Authors used to be deleted without their books, which then pointed at a
+-- hidden author. The Go step of this migration deletes those books with their
+-- reviews, as deleting the authors with cascade=true does now, and logs them.
+SELECT 1;
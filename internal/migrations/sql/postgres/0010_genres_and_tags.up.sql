@@ -0,0 +1,34 @@
+-- Genres and tags classify books. Their names are unique regardless of case,
+-- and the join tables list the genres and tags of each book.
+CREATE TABLE genres (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    version     BIGINT NOT NULL DEFAULT 1,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX idx_genres_name ON genres (LOWER(name));
+
+CREATE TABLE tags (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    version    BIGINT NOT NULL DEFAULT 1,
+    name       TEXT NOT NULL
+);
+CREATE UNIQUE INDEX idx_tags_name ON tags (LOWER(name));
+
+CREATE TABLE book_genres (
+    book_id  BIGINT NOT NULL CONSTRAINT fk_books_genres REFERENCES books (id),
+    genre_id BIGINT NOT NULL CONSTRAINT fk_genres_books REFERENCES genres (id),
+    PRIMARY KEY (book_id, genre_id)
+);
+CREATE INDEX idx_book_genres_genre_id ON book_genres (genre_id);
+
+CREATE TABLE book_tags (
+    book_id BIGINT NOT NULL CONSTRAINT fk_books_tags REFERENCES books (id),
+    tag_id  BIGINT NOT NULL CONSTRAINT fk_tags_books REFERENCES tags (id),
+    PRIMARY KEY (book_id, tag_id)
+);
+CREATE INDEX idx_book_tags_tag_id ON book_tags (tag_id);
@@ -0,0 +1,24 @@
+-- Hierarchical subject classification. Each subject stores its materialized
+-- path of ancestor IDs, like /1/5/12/, and a subtree is selected with a
+-- prefix match on it.
+CREATE TABLE subjects (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    version    BIGINT NOT NULL DEFAULT 1,
+    parent_id  BIGINT CONSTRAINT fk_subjects_children REFERENCES subjects (id),
+    code       TEXT NOT NULL,
+    label      TEXT NOT NULL,
+    path       TEXT NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX idx_subjects_code ON subjects (code);
+CREATE INDEX idx_subjects_parent_id ON subjects (parent_id);
+-- text_pattern_ops lets path LIKE '/1/5/%' use the index whatever the collation
+CREATE INDEX idx_subjects_path ON subjects (path text_pattern_ops);
+
+CREATE TABLE book_subjects (
+    book_id    BIGINT NOT NULL CONSTRAINT fk_books_subjects REFERENCES books (id),
+    subject_id BIGINT NOT NULL CONSTRAINT fk_subjects_books REFERENCES subjects (id),
+    PRIMARY KEY (book_id, subject_id)
+);
+CREATE INDEX idx_book_subjects_subject_id ON book_subjects (subject_id);
@@ -0,0 +1,14 @@
+-- Series of books read in order. A book is in at most one series, at a
+-- position that may be fractional, such as 2.5 for a novella between volumes.
+CREATE TABLE series (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    version     BIGINT NOT NULL DEFAULT 1,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE books ADD COLUMN series_id BIGINT CONSTRAINT fk_books_series REFERENCES series (id);
+ALTER TABLE books ADD COLUMN series_position DOUBLE PRECISION;
+CREATE INDEX idx_books_series ON books (series_id, series_position);
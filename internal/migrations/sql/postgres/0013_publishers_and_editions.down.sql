@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS editions;
+DROP TABLE IF EXISTS publishers;
@@ -0,0 +1,2 @@
+-- The backfilled ISBN-13s are valid and stay when rolling back
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Two books of a series cannot share a position. Saving a book only checked
+-- the other books first, which concurrent writes could both pass. It fails
+-- while two books share a position: move one of them and migrate again.
+CREATE UNIQUE INDEX idx_books_series_position ON books (series_id, series_position) WHERE deleted_at IS NULL;
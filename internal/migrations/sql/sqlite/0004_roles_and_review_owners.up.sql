@@ -0,0 +1,6 @@
+-- Existing accounts become regular users, existing reviews have no owner
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'librarian', 'admin'));
+
+ALTER TABLE reviews ADD COLUMN user_id INTEGER REFERENCES users (id);
+CREATE INDEX idx_reviews_user_id ON reviews (user_id);
@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at    DATETIME,
+    updated_at    DATETIME,
+    deleted_at    DATETIME,
+    name          TEXT NOT NULL,
+    prefix        TEXT NOT NULL,
+    key_hash      TEXT NOT NULL,
+    scopes        TEXT NOT NULL,
+    created_by_id INTEGER REFERENCES users (id),
+    last_used_at  DATETIME
+);
+CREATE INDEX idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
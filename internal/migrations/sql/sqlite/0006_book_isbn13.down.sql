@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_books_isbn13;
+ALTER TABLE books DROP COLUMN isbn13;
@@ -0,0 +1,4 @@
+-- Row versions for optimistic locking, bumped by every update and exposed as ETags
+ALTER TABLE authors ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE books ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE reviews ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
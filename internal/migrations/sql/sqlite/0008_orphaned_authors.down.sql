@@ -0,0 +1,2 @@
+-- The logged books can be restored with POST /books/:id/restore after their author
+SELECT 1;
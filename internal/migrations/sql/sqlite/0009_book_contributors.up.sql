@@ -0,0 +1,13 @@
+-- Authors credited on a book, in order, with their role. Every existing book,
+-- deleted or not, gets its author_id as the primary author.
+CREATE TABLE book_contributors (
+    book_id   INTEGER NOT NULL REFERENCES books (id),
+    author_id INTEGER NOT NULL REFERENCES authors (id),
+    role      TEXT NOT NULL CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position  INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+CREATE INDEX idx_book_contributors_author_id ON book_contributors (author_id);
+
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 0 FROM books WHERE author_id IS NOT NULL;
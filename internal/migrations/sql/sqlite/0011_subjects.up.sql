@@ -0,0 +1,23 @@
+-- Hierarchical subject classification. Each subject stores its materialized
+-- path of ancestor IDs, like /1/5/12/, and a subtree is selected with a
+-- prefix match on it.
+CREATE TABLE subjects (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    version    INTEGER NOT NULL DEFAULT 1,
+    parent_id  INTEGER REFERENCES subjects (id),
+    code       TEXT NOT NULL,
+    label      TEXT NOT NULL,
+    path       TEXT NOT NULL DEFAULT ''
+);
+CREATE UNIQUE INDEX idx_subjects_code ON subjects (code);
+CREATE INDEX idx_subjects_parent_id ON subjects (parent_id);
+CREATE INDEX idx_subjects_path ON subjects (path);
+
+CREATE TABLE book_subjects (
+    book_id    INTEGER NOT NULL REFERENCES books (id),
+    subject_id INTEGER NOT NULL REFERENCES subjects (id),
+    PRIMARY KEY (book_id, subject_id)
+);
+CREATE INDEX idx_book_subjects_subject_id ON book_subjects (subject_id);
@@ -0,0 +1,33 @@
+-- Editions are the published forms of a book, each with its own ISBN, format,
+-- language, page count and publisher. Publisher names are unique regardless of case.
+CREATE TABLE publishers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    version    INTEGER NOT NULL DEFAULT 1,
+    name       TEXT NOT NULL
+);
+CREATE UNIQUE INDEX idx_publishers_name ON publishers (LOWER(name));
+
+CREATE TABLE editions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at   DATETIME,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    version      INTEGER NOT NULL DEFAULT 1,
+    book_id      INTEGER NOT NULL REFERENCES books (id),
+    isbn         TEXT NOT NULL,
+    isbn13       TEXT,
+    format       TEXT NOT NULL DEFAULT '',
+    language     TEXT NOT NULL DEFAULT '',
+    page_count   INTEGER NOT NULL DEFAULT 0,
+    publisher_id INTEGER REFERENCES publishers (id)
+);
+CREATE INDEX idx_editions_deleted_at ON editions (deleted_at);
+CREATE INDEX idx_editions_book_id ON editions (book_id);
+CREATE INDEX idx_editions_publisher_id ON editions (publisher_id);
+CREATE UNIQUE INDEX idx_editions_isbn13 ON editions (isbn13) WHERE deleted_at IS NULL;
+
+-- Every existing book becomes a work with its ISBN as its first edition
+INSERT INTO editions (created_at, updated_at, deleted_at, book_id, isbn, isbn13)
+SELECT created_at, updated_at, deleted_at, id, COALESCE(isbn, ''), isbn13 FROM books ORDER BY id;
@@ -0,0 +1,18 @@
+-- Physical copies of books, each with a barcode that is unique among the
+-- copies of books that are not deleted.
+CREATE TABLE copies (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at       DATETIME,
+    updated_at       DATETIME,
+    deleted_at       DATETIME,
+    version          INTEGER NOT NULL DEFAULT 1,
+    book_id          INTEGER NOT NULL REFERENCES books (id),
+    barcode          TEXT NOT NULL,
+    shelf_location   TEXT NOT NULL DEFAULT '',
+    condition        TEXT NOT NULL DEFAULT '',
+    acquisition_date TEXT NOT NULL DEFAULT '',
+    status           TEXT NOT NULL DEFAULT 'available'
+);
+CREATE INDEX idx_copies_deleted_at ON copies (deleted_at);
+CREATE INDEX idx_copies_book_status ON copies (book_id, status);
+CREATE UNIQUE INDEX idx_copies_barcode ON copies (barcode) WHERE deleted_at IS NULL;
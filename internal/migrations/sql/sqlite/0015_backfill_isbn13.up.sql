@@ -0,0 +1,4 @@
+-- Books saved before 0006 have no normalized ISBN-13, and neither have the
+-- first editions 0013 made of them. The Go step of this migration fills them
+-- in, and logs the ISBNs it cannot normalize, which keep NULL.
+SELECT 1;
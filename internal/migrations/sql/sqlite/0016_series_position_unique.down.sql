@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_books_series_position;
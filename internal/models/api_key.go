@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKey is a credential for machine clients. Only the SHA-256 hash of the
+// key is stored; Prefix is kept so admins can tell keys apart.
+type APIKey struct {
+	gorm.Model
+	Name        string
+	Prefix      string
+	KeyHash     string
+	Scopes      string
+	CreatedByID uint
+	LastUsedAt  *time.Time
+}
@@ -4,8 +4,10 @@ import "gorm.io/gorm"
 
 type Author struct {
 	gorm.Model
-	Name      string
-	Biography string
-	BirthDate string
-	Books     []Book
+	Version       uint `gorm:"default:1"` // incremented by every update, for optimistic locking
+	Name          string
+	Biography     string
+	BirthDate     string
+	Books         []Book            // books of which the author is the primary author
+	Contributions []BookContributor // every credit of the author, in any role
 }
@@ -4,11 +4,27 @@ import "gorm.io/gorm"
 
 type Book struct {
 	gorm.Model
+	Version         uint `gorm:"default:1"` // incremented by every update, for optimistic locking
 	Title           string
-	AuthorID        uint
+	AuthorID        uint // the primary author, the first contributor in the author role
 	Author          Author
-	ISBN            string
+	Contributors    []BookContributor
+	ISBN            string  // of the first edition, as entered, for display
+	ISBN13          *string // of the first edition, normalized and unique, nil for books saved without an ISBN before normalization
 	PublicationYear int
 	Description     string
 	Reviews         []Review
+	Editions        []Edition // in the order they were added, the first one has ISBN
+	Copies          []Copy    // not loaded with the book, which counts them in Availability
+	Genres          []Genre   `gorm:"many2many:book_genres"`
+	Tags            []Tag     `gorm:"many2many:book_tags"`
+	Subjects        []Subject `gorm:"many2many:book_subjects"`
+	SeriesID        *uint
+	Series          *Series
+	SeriesPosition  *float64 // set together with SeriesID, reading order within the series
+	// The books before and after this one in its series, loaded with its details
+	PreviousInSeries *Book `gorm:"-"`
+	NextInSeries     *Book `gorm:"-"`
+	// The number of copies by status, loaded with the book
+	Availability *Availability `gorm:"-"`
 }
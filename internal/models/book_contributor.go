@@ -0,0 +1,21 @@
+package models
+
+// Contributor roles of an author on a book
+const (
+	ContributorAuthor      = "author"
+	ContributorEditor      = "editor"
+	ContributorTranslator  = "translator"
+	ContributorIllustrator = "illustrator"
+)
+
+// BookContributor credits an author on a book in a role. An author can have
+// several roles on the same book. Position orders the contributors of a book
+// as they are credited.
+type BookContributor struct {
+	BookID   uint   `gorm:"primaryKey;autoIncrement:false"`
+	AuthorID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Role     string `gorm:"primaryKey"`
+	Position int
+	Book     Book
+	Author   Author
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Copy statuses
+const (
+	CopyAvailable = "available"
+	CopyOnLoan    = "on_loan"
+	CopyLost      = "lost"
+	CopyRepair    = "repair"
+)
+
+// Copy conditions
+const (
+	ConditionNew  = "new"
+	ConditionGood = "good"
+	ConditionFair = "fair"
+	ConditionPoor = "poor"
+)
+
+// Copy is a physical item of a book on the library's shelves, identified by
+// its barcode. Copies are soft deleted together with their book.
+type Copy struct {
+	ID              uint `gorm:"primarykey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	Version         uint           `gorm:"default:1"` // incremented by every update, for optimistic locking
+	BookID          uint
+	Barcode         string // unique among the copies of books that are not deleted
+	ShelfLocation   string
+	Condition       string // one of the Condition constants, or empty when not assessed
+	AcquisitionDate string // YYYY-MM-DD, or empty when unknown
+	Status          string // one of the Copy status constants
+}
+
+// Availability counts the copies of a book by status
+type Availability struct {
+	Total     int64
+	Available int64
+	OnLoan    int64
+	Lost      int64
+	Repair    int64
+}
+
+// Add counts a copy with the given status
+func (a *Availability) Add(status string, count int64) {
+	a.Total += count
+	switch status {
+	case CopyAvailable:
+		a.Available += count
+	case CopyOnLoan:
+		a.OnLoan += count
+	case CopyLost:
+		a.Lost += count
+	case CopyRepair:
+		a.Repair += count
+	}
+}
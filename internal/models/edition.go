@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Edition formats
+const (
+	FormatHardcover = "hardcover"
+	FormatPaperback = "paperback"
+	FormatEbook     = "ebook"
+	FormatAudiobook = "audiobook"
+)
+
+// Edition is one published form of a book, the work it belongs to, with its
+// own ISBN. Every book has at least one, its first edition, which carries the
+// book's ISBN. Editions are soft deleted together with their book.
+type Edition struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Version     uint           `gorm:"default:1"` // incremented by every update, for optimistic locking
+	BookID      uint
+	ISBN        string  // as entered, for display
+	ISBN13      *string // normalized and unique, nil for books saved without an ISBN before normalization
+	Format      string  // one of the Format constants, or empty when unknown
+	Language    string  // BCP 47 language tag, or empty when unknown
+	PageCount   int
+	PublisherID *uint
+	Publisher   *Publisher
+}
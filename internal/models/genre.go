@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Genre classifies books for browsing. A book can be in several genres.
+// Deleting a genre removes it, and takes it off its books, rather than
+// soft deleting it. The same goes for tags, subjects, series and publishers,
+// only authors, books and reviews are soft deleted.
+type Genre struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     uint `gorm:"default:1"` // incremented by every update, for optimistic locking
+	Name        string
+	Description string
+}
+
+// BookGenre puts a book in a genre, the join table of Book.Genres
+type BookGenre struct {
+	BookID  uint `gorm:"primaryKey;autoIncrement:false"`
+	GenreID uint `gorm:"primaryKey;autoIncrement:false"`
+}
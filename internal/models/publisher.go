@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Publisher publishes editions of books
+type Publisher struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   uint `gorm:"default:1"` // incremented by every update, for optimistic locking
+	Name      string
+}
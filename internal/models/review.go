@@ -4,9 +4,12 @@ import "gorm.io/gorm"
 
 type Review struct {
 	gorm.Model
+	Version    uint `gorm:"default:1"` // incremented by every update, for optimistic locking
 	Rating     int
 	Comment    string
 	DatePosted string
 	BookID     uint
 	Book       Book
+	UserID     *uint
+	User       *User
 }
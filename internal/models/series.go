@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Series groups books that are read in order. A book is in at most one
+// series, at a position that may be fractional for novellas between volumes.
+type Series struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     uint `gorm:"default:1"` // incremented by every update, for optimistic locking
+	Name        string
+	Description string
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subject is a node of the hierarchical shelf classification, such as a Dewey
+// class. Path materializes its place in the tree as the IDs from the top
+// level subject down to this one, like "/1/5/12/", so that the subtree of a
+// subject is every subject whose path starts with its path.
+type Subject struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   uint  `gorm:"default:1"` // incremented by every update, for optimistic locking
+	ParentID  *uint // nil for top level subjects
+	Code      string
+	Label     string
+	Path      string
+}
+
+// SubjectPath returns the path of the subject with the given ID under the
+// subject with parentPath, which is "" for a top level subject
+func SubjectPath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// AncestorIDs returns the IDs of the ancestors of the subject from the top level down
+func (s Subject) AncestorIDs() []uint {
+	parts := strings.Split(strings.Trim(s.Path, "/"), "/")
+	ids := make([]uint, 0, len(parts))
+	for _, part := range parts[:len(parts)-1] {
+		if id, err := strconv.ParseUint(part, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// BookSubject files a book under a subject, the join table of Book.Subjects
+type BookSubject struct {
+	BookID    uint `gorm:"primaryKey;autoIncrement:false"`
+	SubjectID uint `gorm:"primaryKey;autoIncrement:false"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubjectPath(t *testing.T) {
+	tests := []struct {
+		parentPath string
+		id         uint
+		want       string
+	}{
+		{parentPath: "", id: 1, want: "/1/"},
+		{parentPath: "/1/", id: 5, want: "/1/5/"},
+		{parentPath: "/1/5/", id: 12, want: "/1/5/12/"},
+		{parentPath: "/10/", id: 1, want: "/10/1/"},
+	}
+
+	for _, tt := range tests {
+		if got := SubjectPath(tt.parentPath, tt.id); got != tt.want {
+			t.Errorf("SubjectPath(%q, %d) = %q, want %q", tt.parentPath, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestSubjectAncestorIDs(t *testing.T) {
+	tests := []struct {
+		path string
+		want []uint
+	}{
+		{path: "/1/", want: []uint{}},
+		{path: "/1/5/", want: []uint{1}},
+		{path: "/1/5/12/", want: []uint{1, 5}},
+		{path: "/10/1/100/", want: []uint{10, 1}},
+		{path: "", want: []uint{}},
+	}
+
+	for _, tt := range tests {
+		if got := (Subject{Path: tt.path}).AncestorIDs(); !slices.Equal(got, tt.want) {
+			t.Errorf("AncestorIDs() of %q = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Tag is a free-form subject label on books
+type Tag struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   uint `gorm:"default:1"` // incremented by every update, for optimistic locking
+	Name      string
+}
+
+// BookTag puts a tag on a book, the join table of Book.Tags
+type BookTag struct {
+	BookID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID  uint `gorm:"primaryKey;autoIncrement:false"`
+}
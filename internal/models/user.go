@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
+type User struct {
+	gorm.Model
+	Email        string
+	Name         string
+	PasswordHash string
+	Role         string
+}
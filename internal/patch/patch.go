@@ -0,0 +1,367 @@
+// Package patch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902)
+// documents to JSON values. Both work on the encoded JSON so that handlers can
+// patch the request form of a resource and validate the result like a PUT body.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalid is returned for malformed patches and operations on paths that do not exist
+	ErrInvalid = errors.New("invalid patch")
+	// ErrTestFailed is returned when a JSON Patch test operation does not match the document
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// Merge applies a JSON Merge Patch to doc. Members of the patch replace those
+// of doc, null members remove them and nested objects are merged recursively.
+func Merge(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// Apply applies a JSON Patch, an array of add, remove, replace, move, copy and
+// test operations, to doc. Operations run in order and the patch is all or
+// nothing: an error from any operation leaves no partial result.
+func Apply(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: a JSON Patch must be an array of operations", ErrInvalid)
+	}
+
+	for i, raw := range operations {
+		target, err = applyOperation(target, raw)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func applyOperation(doc any, raw map[string]json.RawMessage) (any, error) {
+	var op string
+	if err := json.Unmarshal(raw["op"], &op); err != nil {
+		return nil, fmt.Errorf("%w: op must be a string", ErrInvalid)
+	}
+	path, err := pointerMember(raw, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "add", "replace", "test":
+		encoded, ok := raw["value"]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires a value", ErrInvalid, op)
+		}
+		value, err := decode(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		switch op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, fmt.Errorf("%w: value at %s does not match", ErrTestFailed, pointerString(path))
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := pointerMember(raw, "from")
+		if err != nil {
+			return nil, err
+		}
+		if op == "move" {
+			if len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+				return nil, fmt.Errorf("%w: cannot move %s into itself", ErrInvalid, pointerString(from))
+			}
+			doc, value, err := remove(doc, from)
+			if err != nil {
+				return nil, err
+			}
+			return add(doc, path, value)
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, clone(value))
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalid, op)
+	}
+}
+
+// add sets an object member or inserts into an array, "-" appending to it
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch parent := parent.(type) {
+		case map[string]any:
+			parent[token] = value
+			return parent, nil
+		case []any:
+			i := len(parent)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(parent)+1); err != nil {
+					return nil, err
+				}
+			}
+			return slices.Insert(parent, i, value), nil
+		default:
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalid, pointerString(path))
+		}
+	})
+}
+
+// replace sets a member or element that must already exist
+func replace(doc any, path []string, value any) (any, error) {
+	if _, err := get(doc, path); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent any, token string) (any, error) {
+		return setChild(parent, token, value), nil
+	})
+}
+
+// remove deletes a member or element and returns it
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalid)
+	}
+	removed, err := get(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err = update(doc, path, func(parent any, token string) (any, error) {
+		switch parent := parent.(type) {
+		case map[string]any:
+			delete(parent, token)
+		case []any:
+			i, _ := arrayIndex(token, len(parent))
+			return slices.Delete(parent, i, i+1), nil
+		}
+		return parent, nil
+	})
+	return doc, removed, err
+}
+
+// update walks to the parent of the last token of path and replaces it with
+// the result of fn, rebuilding the containers above it on the way back
+func update(node any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+	next, err := child(node, path[0])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := update(next, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return setChild(node, path[0], updated), nil
+}
+
+// get returns the value at path
+func get(doc any, path []string) (any, error) {
+	node := doc
+	for i, token := range path {
+		next, err := child(node, token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s does not exist", ErrInvalid, pointerString(path[:i+1]))
+		}
+		node = next
+	}
+	return node, nil
+}
+
+func child(node any, token string) (any, error) {
+	switch node := node.(type) {
+	case map[string]any:
+		value, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalid, token)
+		}
+		return value, nil
+	case []any:
+		i, err := arrayIndex(token, len(node))
+		if err != nil {
+			return nil, err
+		}
+		return node[i], nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not inside an object or array", ErrInvalid, token)
+	}
+}
+
+// setChild replaces an existing member or element of node
+func setChild(node any, token string, value any) any {
+	switch node := node.(type) {
+	case map[string]any:
+		node[token] = value
+	case []any:
+		i, _ := arrayIndex(token, len(node))
+		node[i] = value
+	}
+	return node
+}
+
+// arrayIndex parses an array index token, which must be below limit
+func arrayIndex(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalid, token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= limit {
+		return 0, fmt.Errorf("%w: array index %s is out of range", ErrInvalid, token)
+	}
+	return i, nil
+}
+
+// pointerMember reads a JSON Pointer member of an operation, such as path or from
+func pointerMember(raw map[string]json.RawMessage, name string) ([]string, error) {
+	var pointer string
+	if err := json.Unmarshal(raw[name], &pointer); err != nil {
+		return nil, fmt.Errorf("%w: %s must be a JSON Pointer string", ErrInvalid, name)
+	}
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %s %q must start with /", ErrInvalid, name, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerString(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// decode parses a single JSON value, keeping numbers exact
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// clone deep copies a decoded JSON value
+func clone(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for name, member := range value {
+			copied[name] = clone(member)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, element := range value {
+			copied[i] = clone(element)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// equal compares decoded JSON values, numbers by their value
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, member := range a {
+			other, ok := b[name]
+			if !ok || !equal(member, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equal)
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,162 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// canonical re-encodes a JSON value with sorted members
+func canonical(t *testing.T, data string) string {
+	t.Helper()
+	value, err := decode([]byte(data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("encode %s: %v", data, err)
+	}
+	return string(encoded)
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "replace member", doc: `{"a":1,"b":2}`, patch: `{"a":3}`, want: `{"a":3,"b":2}`},
+		{name: "add member", doc: `{"a":1}`, patch: `{"b":2}`, want: `{"a":1,"b":2}`},
+		{name: "null removes member", doc: `{"a":1,"b":2}`, patch: `{"a":null}`, want: `{"b":2}`},
+		{name: "null removes missing member", doc: `{"a":1}`, patch: `{"b":null}`, want: `{"a":1}`},
+		{name: "nested null", doc: `{"a":{"b":1,"c":2}}`, patch: `{"a":{"b":null}}`, want: `{"a":{"c":2}}`},
+		{name: "nested merge", doc: `{"a":{"b":1}}`, patch: `{"a":{"c":2}}`, want: `{"a":{"b":1,"c":2}}`},
+		{name: "null in new object is dropped", doc: `{}`, patch: `{"a":{"b":null}}`, want: `{"a":{}}`},
+		{name: "array replaced whole", doc: `{"a":[1,2,3]}`, patch: `{"a":[4]}`, want: `{"a":[4]}`},
+		{name: "object replaces scalar", doc: `{"a":1}`, patch: `{"a":{"b":2}}`, want: `{"a":{"b":2}}`},
+		{name: "non object patch replaces doc", doc: `{"a":1}`, patch: `[1]`, want: `[1]`},
+		{name: "large numbers kept exact", doc: `{"a":1}`, patch: `{"a":12345678901234567890}`, want: `{"a":12345678901234567890}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if string(got) != canonical(t, tt.want) {
+				t.Errorf("Merge() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	for _, patch := range []string{``, `{`, `{"a":1} {}`} {
+		if _, err := Merge([]byte(`{}`), []byte(patch)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Merge(%q) error = %v, want ErrInvalid", patch, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":4}`
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{name: "add member", patch: `[{"op":"add","path":"/d","value":5}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":4,"d":5}`},
+		{name: "add replaces member", patch: `[{"op":"add","path":"/a/b","value":[]}]`,
+			want: `{"a":{"b":[],"c":[1,2,3]},"x/y":2,"m~n":3,"":4}`},
+		{name: "add inserts into array", patch: `[{"op":"add","path":"/a/c/1","value":9}]`,
+			want: `{"a":{"b":1,"c":[1,9,2,3]},"x/y":2,"m~n":3,"":4}`},
+		{name: "add at array length", patch: `[{"op":"add","path":"/a/c/3","value":9}]`,
+			want: `{"a":{"b":1,"c":[1,2,3,9]},"x/y":2,"m~n":3,"":4}`},
+		{name: "add appends with -", patch: `[{"op":"add","path":"/a/c/-","value":9}]`,
+			want: `{"a":{"b":1,"c":[1,2,3,9]},"x/y":2,"m~n":3,"":4}`},
+		{name: "add whole document", patch: `[{"op":"add","path":"","value":[1]}]`, want: `[1]`},
+		{name: "pointer escaping ~1", patch: `[{"op":"replace","path":"/x~1y","value":5}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":5,"m~n":3,"":4}`},
+		{name: "pointer escaping ~0", patch: `[{"op":"remove","path":"/m~0n"}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"":4}`},
+		{name: "pointer ~01 is ~1", patch: `[{"op":"add","path":"/~01","value":5}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":4,"~1":5}`},
+		{name: "empty member name", patch: `[{"op":"replace","path":"/","value":5}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":5}`},
+		{name: "remove array element", patch: `[{"op":"remove","path":"/a/c/0"}]`,
+			want: `{"a":{"b":1,"c":[2,3]},"x/y":2,"m~n":3,"":4}`},
+		{name: "replace array element", patch: `[{"op":"replace","path":"/a/c/2","value":"z"}]`,
+			want: `{"a":{"b":1,"c":[1,2,"z"]},"x/y":2,"m~n":3,"":4}`},
+		{name: "move member", patch: `[{"op":"move","from":"/a/b","path":"/b"}]`,
+			want: `{"a":{"c":[1,2,3]},"b":1,"x/y":2,"m~n":3,"":4}`},
+		{name: "move array element", patch: `[{"op":"move","from":"/a/c/0","path":"/a/c/-"}]`,
+			want: `{"a":{"b":1,"c":[2,3,1]},"x/y":2,"m~n":3,"":4}`},
+		{name: "move to itself", patch: `[{"op":"move","from":"/a","path":"/a"}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":4}`},
+		{name: "move into a sibling with a longer name", patch: `[{"op":"move","from":"/a/b","path":"/a/bb"}]`,
+			want: `{"a":{"bb":1,"c":[1,2,3]},"x/y":2,"m~n":3,"":4}`},
+		{name: "copy is deep", patch: `[{"op":"copy","from":"/a","path":"/d"},{"op":"add","path":"/d/c/-","value":4}]`,
+			want: `{"a":{"b":1,"c":[1,2,3]},"d":{"b":1,"c":[1,2,3,4]},"x/y":2,"m~n":3,"":4}`},
+		{name: "passing test", patch: `[{"op":"test","path":"/a","value":{"c":[1,2,3.0],"b":1}},{"op":"remove","path":"/a"}]`,
+			want: `{"x/y":2,"m~n":3,"":4}`},
+		{name: "no operations", patch: `[]`, want: doc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if string(got) != canonical(t, tt.want) {
+				t.Errorf("Apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	doc := `{"a":{"b":1,"c":[1,2,3]},"s":"x"}`
+	tests := []struct {
+		name  string
+		patch string
+		want  error
+	}{
+		{name: "failing test", patch: `[{"op":"test","path":"/a/b","value":2}]`, want: ErrTestFailed},
+		{name: "failing test of type", patch: `[{"op":"test","path":"/a/b","value":"1"}]`, want: ErrTestFailed},
+		{name: "test of member removed earlier", patch: `[{"op":"remove","path":"/s"},{"op":"test","path":"/s","value":"x"}]`, want: ErrInvalid},
+		{name: "test of missing member", patch: `[{"op":"test","path":"/z","value":1}]`, want: ErrInvalid},
+		{name: "move into own child", patch: `[{"op":"move","from":"/a","path":"/a/d"}]`, want: ErrInvalid},
+		{name: "move into own array", patch: `[{"op":"move","from":"/a/c","path":"/a/c/-"}]`, want: ErrInvalid},
+		{name: "move whole document", patch: `[{"op":"move","from":"","path":"/d"}]`, want: ErrInvalid},
+		{name: "move from missing member", patch: `[{"op":"move","from":"/z","path":"/d"}]`, want: ErrInvalid},
+		{name: "remove with -", patch: `[{"op":"remove","path":"/a/c/-"}]`, want: ErrInvalid},
+		{name: "replace with -", patch: `[{"op":"replace","path":"/a/c/-","value":1}]`, want: ErrInvalid},
+		{name: "remove missing member", patch: `[{"op":"remove","path":"/z"}]`, want: ErrInvalid},
+		{name: "remove whole document", patch: `[{"op":"remove","path":""}]`, want: ErrInvalid},
+		{name: "replace missing member", patch: `[{"op":"replace","path":"/z","value":1}]`, want: ErrInvalid},
+		{name: "add past array end", patch: `[{"op":"add","path":"/a/c/4","value":1}]`, want: ErrInvalid},
+		{name: "add under missing parent", patch: `[{"op":"add","path":"/z/y","value":1}]`, want: ErrInvalid},
+		{name: "add under scalar", patch: `[{"op":"add","path":"/s/y","value":1}]`, want: ErrInvalid},
+		{name: "leading zero index", patch: `[{"op":"replace","path":"/a/c/01","value":1}]`, want: ErrInvalid},
+		{name: "negative index", patch: `[{"op":"replace","path":"/a/c/-1","value":1}]`, want: ErrInvalid},
+		{name: "pointer without slash", patch: `[{"op":"add","path":"a","value":1}]`, want: ErrInvalid},
+		{name: "missing value", patch: `[{"op":"add","path":"/d"}]`, want: ErrInvalid},
+		{name: "missing path", patch: `[{"op":"remove"}]`, want: ErrInvalid},
+		{name: "unknown op", patch: `[{"op":"drop","path":"/a"}]`, want: ErrInvalid},
+		{name: "not an array", patch: `{"op":"remove","path":"/a"}`, want: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(doc), []byte(tt.patch))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Apply() = %s, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository returns a GORM-backed APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return translateError(r.db.Create(key).Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	result := r.db.First(&key, id)
+	return &key, translateError(result.Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) GetByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	result := r.db.Where("key_hash = ?", hash).First(&key)
+	return &key, translateError(result.Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) GetAll(page, pageSize int) ([]models.APIKey, int64, error) {
+	var keys []models.APIKey
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.APIKey{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceAPIKey)
+	}
+
+	// Get paginated keys
+	offset := (page - 1) * pageSize
+	result := r.db.Order("id ASC").Offset(offset).Limit(pageSize).Find(&keys)
+	return keys, count, translateError(result.Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) Update(key *models.APIKey) error {
+	return translateError(r.db.Save(key).Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	return translateError(r.db.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error, resourceAPIKey)
+}
+
+func (r *apiKeyRepository) Delete(id uint) error {
+	return translateError(r.db.Delete(&models.APIKey{}, id).Error, resourceAPIKey)
+}
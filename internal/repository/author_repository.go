@@ -1,30 +1,194 @@
 package repository
 
 import (
-	"go-rest-api/internal/database"
 	"go-rest-api/internal/models"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
 )
 
-func CreateAuthor(author *models.Author) error {
-	return database.DB.Create(author).Error
+type authorRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorRepository returns a GORM-backed AuthorRepository
+func NewAuthorRepository(db *gorm.DB) AuthorRepository {
+	return &authorRepository{db: db}
 }
 
-func GetAuthorByID(id uint) (*models.Author, error) {
+func (r *authorRepository) Create(author *models.Author) error {
+	return translateError(r.db.Create(author).Error, resourceAuthor)
+}
+
+func (r *authorRepository) GetByID(id uint) (*models.Author, error) {
 	var author models.Author
-	result := database.DB.Preload("Books").First(&author, id)
-	return &author, result.Error
+	result := r.db.Preload("Books").
+		Preload("Contributions", func(db *gorm.DB) *gorm.DB {
+			return db.Where("book_id IN (?)", liveBookIDs(r.db)).Order("book_id, position")
+		}).
+		Preload("Contributions.Book").
+		First(&author, id)
+	return &author, translateError(result.Error, resourceAuthor)
+}
+
+// liveBookIDs selects the IDs of the books that are not deleted
+func liveBookIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Book{}).Select("id")
 }
 
-func GetAllAuthors() ([]models.Author, error) {
+func (r *authorRepository) GetAll(filter AuthorFilter, page, pageSize int) ([]models.Author, int64, error) {
+	var authors []models.Author
+	var count int64
+
+	// Get total count
+	if err := r.filtered(filter).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceAuthor)
+	}
+
+	// Get paginated authors, books only on request
+	offset := (page - 1) * pageSize
+	query := r.filtered(filter).Order("id ASC").Offset(offset).Limit(pageSize)
+	if filter.IncludeBooks {
+		query = query.Preload("Books")
+	}
+	result := query.Find(&authors)
+	return authors, count, translateError(result.Error, resourceAuthor)
+}
+
+func (r *authorRepository) GetAllByCursor(filter AuthorFilter, cursor *Cursor, limit int) ([]models.Author, KeysetPage, error) {
+	values, err := idCursorKeys(cursor)
+	if err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceAuthor)
+	}
+
+	query := r.filtered(filter)
+	if filter.IncludeBooks {
+		query = query.Preload("Books")
+	}
+
 	var authors []models.Author
-	result := database.DB.Preload("Books").Find(&authors)
-	return authors, result.Error
+	if err := keysetQuery(query, "authors", idKeys, idColumns, values, cursor, limit).Find(&authors).Error; err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceAuthor)
+	}
+
+	count, page := keysetPage(len(authors), limit, nil, cursor, func(i int) []string {
+		return []string{formatSortKey(authors[i].ID)}
+	})
+	authors = authors[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(authors)
+	}
+	return authors, page, nil
+}
+
+// filtered applies the conditions of an AuthorFilter to an authors query
+func (r *authorRepository) filtered(filter AuthorFilter) *gorm.DB {
+	query := r.db.Model(&models.Author{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	return query
+}
+
+func (r *authorRepository) CountBooks(authorIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		AuthorID  uint
+		BookCount int64
+	}
+	counts := make(map[uint]int64, len(authorIDs))
+	if len(authorIDs) == 0 {
+		return counts, nil
+	}
+
+	// Books are counted once whatever roles the author has on them
+	err := r.db.Model(&models.BookContributor{}).
+		Select("author_id, COUNT(DISTINCT book_id) AS book_count").
+		Where("author_id IN ? AND book_id IN (?)", authorIDs, liveBookIDs(r.db)).
+		Group("author_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err, resourceAuthor)
+	}
+
+	for _, row := range rows {
+		counts[row.AuthorID] = row.BookCount
+	}
+	return counts, nil
 }
 
-func UpdateAuthor(author *models.Author) error {
-	return database.DB.Save(author).Error
+func (r *authorRepository) Update(author *models.Author) error {
+	return updateVersioned(r.db, author, &author.Version, resourceAuthor)
+}
+
+func (r *authorRepository) Delete(id uint, deletion AuthorDeletion) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		credited := tx.Model(&models.BookContributor{}).Select("book_id").
+			Where("author_id = ? AND book_id IN (?)", id, liveBookIDs(tx))
+		changed := map[string]any{"version": gorm.Expr("version + 1"), "updated_at": time.Now()}
+
+		switch {
+		case deletion.ReassignTo != nil:
+			to := *deletion.ReassignTo
+			// The new author must not have been deleted in the meantime
+			if err := tx.First(&models.Author{}, to).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Book{}).Where("id IN (?)", credited).UpdateColumns(changed).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Book{}).Where("author_id = ?", id).UpdateColumn("author_id", to).Error; err != nil {
+				return err
+			}
+			// Credits the new author already has in the same role are merged into theirs
+			if err := tx.Where("author_id = ? AND EXISTS (?)", id,
+				tx.Table("book_contributors AS existing").Select("1").
+					Where("existing.book_id = book_contributors.book_id AND existing.role = book_contributors.role AND existing.author_id = ?", to),
+			).Delete(&models.BookContributor{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.BookContributor{}).Where("author_id = ? AND book_id IN (?)", id, liveBookIDs(tx)).
+				UpdateColumn("author_id", to).Error; err != nil {
+				return err
+			}
+		case deletion.Cascade:
+			// Books of which the author is the primary author are deleted,
+			// on the others the author is no longer credited
+			if err := deleteBooks(tx, "author_id = ?", id); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Book{}).Where("id IN (?)", credited).UpdateColumns(changed).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("author_id = ? AND book_id IN (?)", id, liveBookIDs(tx)).Delete(&models.BookContributor{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Books crediting a deleted author would point at a hidden record
+		var books int64
+		if err := credited.Count(&books).Error; err != nil {
+			return err
+		}
+		if books > 0 {
+			return errInUse(resourceAuthor)
+		}
+		return tx.Delete(&models.Author{}, id).Error
+	})
+	return translateError(err, resourceAuthor)
+}
+
+func (r *authorRepository) GetDeletedByID(id uint) (*models.Author, error) {
+	var author models.Author
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&author, id)
+	return &author, translateError(result.Error, resourceAuthor)
 }
 
-func DeleteAuthor(id uint) error {
-	return database.DB.Delete(&models.Author{}, id).Error
+func (r *authorRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Author{}).Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumns(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return errNotFound(resourceAuthor)
+	}
+	return translateError(result.Error, resourceAuthor)
 }
@@ -1,49 +1,443 @@
 package repository
 
 import (
-	"go-rest-api/internal/database"
+	"errors"
+	"go-rest-api/internal/apperr"
 	"go-rest-api/internal/models"
+	"slices"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func CreateBook(book *models.Book) error {
-	return database.DB.Create(book).Error
+type bookRepository struct {
+	db *gorm.DB
+}
+
+// NewBookRepository returns a GORM-backed BookRepository
+func NewBookRepository(db *gorm.DB) BookRepository {
+	return &bookRepository{db: db}
+}
+
+func (r *bookRepository) Create(book *models.Book) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(book).Error; err != nil {
+			return err
+		}
+		if err := saveFirstEdition(tx, book); err != nil {
+			return err
+		}
+		if err := replaceContributors(tx, book); err != nil {
+			return err
+		}
+		if err := replaceClassification(tx, book); err != nil {
+			return err
+		}
+		book.Availability = &models.Availability{}
+		return nil
+	})
+	return r.translateBookError(err, *book)
+}
+
+// translateBookError reports books that collide with the unique index on
+// series positions as book_position_taken. GORM reports them like books with
+// an ISBN in use, so they are told apart by looking for the other book.
+func (r *bookRepository) translateBookError(err error, book models.Book) error {
+	err = translateError(err, resourceBook)
+	if apperr.From(err).Code == "book_exists" && book.SeriesID != nil && book.SeriesPosition != nil {
+		var taken int64
+		result := r.db.Model(&models.Book{}).
+			Where("series_id = ? AND series_position = ? AND id <> ?", *book.SeriesID, *book.SeriesPosition, book.ID).
+			Count(&taken)
+		if result.Error == nil && taken > 0 {
+			return errPositionTaken()
+		}
+	}
+	return err
+}
+
+// saveFirstEdition gives the first edition of a book the book's ISBN, creating
+// it for a new book. An ISBN another edition has fails with book_exists.
+func saveFirstEdition(tx *gorm.DB, book *models.Book) error {
+	var editions []models.Edition
+	if err := tx.Where("book_id = ?", book.ID).Order("id").Limit(1).Find(&editions).Error; err != nil {
+		return err
+	}
+	if len(editions) == 0 {
+		first := models.Edition{BookID: book.ID, ISBN: book.ISBN, ISBN13: book.ISBN13}
+		if err := tx.Omit("Publisher").Create(&first).Error; err != nil {
+			return err
+		}
+		book.Editions = append([]models.Edition{first}, book.Editions...)
+		return nil
+	}
+
+	first := editions[0]
+	if first.ISBN == book.ISBN {
+		return nil
+	}
+	first.ISBN, first.ISBN13 = book.ISBN, book.ISBN13
+	if err := updateVersioned(tx, &first, &first.Version, resourceBook); err != nil {
+		return err
+	}
+	// Keep the loaded editions in step with the stored ones
+	if len(book.Editions) > 0 && book.Editions[0].ID == first.ID {
+		first.Publisher = book.Editions[0].Publisher
+		book.Editions[0] = first
+	}
+	return nil
+}
+
+// replaceContributors stores book.Contributors, in order, as the only contributors of the book
+func replaceContributors(tx *gorm.DB, book *models.Book) error {
+	if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookContributor{}).Error; err != nil {
+		return err
+	}
+	if len(book.Contributors) == 0 {
+		return nil
+	}
+	for i := range book.Contributors {
+		book.Contributors[i].BookID = book.ID
+		book.Contributors[i].Position = i
+	}
+	return tx.Omit(clause.Associations).Create(&book.Contributors).Error
+}
+
+// replaceClassification stores book.Genres, book.Tags and book.Subjects as
+// the only genres, tags and subjects of the book
+func replaceClassification(tx *gorm.DB, book *models.Book) error {
+	for _, links := range []any{&models.BookGenre{}, &models.BookTag{}, &models.BookSubject{}} {
+		if err := tx.Where("book_id = ?", book.ID).Delete(links).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(book.Genres) > 0 {
+		links := make([]models.BookGenre, len(book.Genres))
+		for i, genre := range book.Genres {
+			links[i] = models.BookGenre{BookID: book.ID, GenreID: genre.ID}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+	}
+	if len(book.Tags) > 0 {
+		links := make([]models.BookTag, len(book.Tags))
+		for i, tag := range book.Tags {
+			links[i] = models.BookTag{BookID: book.ID, TagID: tag.ID}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+	}
+	if len(book.Subjects) > 0 {
+		links := make([]models.BookSubject, len(book.Subjects))
+		for i, subject := range book.Subjects {
+			links[i] = models.BookSubject{BookID: book.ID, SubjectID: subject.ID}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// byName orders genres or tags like their listings
+func byName(db *gorm.DB) *gorm.DB {
+	return db.Order("LOWER(name), id")
 }
 
-func GetBookByID(id uint) (*models.Book, error) {
+// withDetails preloads the author, the contributors in credit order, the
+// reviews, the editions with their publishers, the genres, tags and subjects
+// and the series of books
+func withDetails(query *gorm.DB) *gorm.DB {
+	return query.Preload("Author").
+		Preload("Contributors", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Contributors.Author").
+		Preload("Reviews").
+		Preload("Editions", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Editions.Publisher").
+		Preload("Genres", byName).
+		Preload("Tags", byName).
+		Preload("Subjects", func(db *gorm.DB) *gorm.DB { return db.Order("code") }).
+		Preload("Series")
+}
+
+// loadSeriesNeighbours sets the books before and after a book in its series,
+// in the order of SeriesRepository.GetBooks
+func loadSeriesNeighbours(db *gorm.DB, book *models.Book) error {
+	book.PreviousInSeries, book.NextInSeries = nil, nil
+	if book.SeriesID == nil || book.SeriesPosition == nil {
+		return nil
+	}
+
+	series, position := *book.SeriesID, *book.SeriesPosition
+	var previous, next []models.Book
+	err := db.Where("series_id = ? AND (series_position < ? OR (series_position = ? AND id < ?))", series, position, position, book.ID).
+		Order("series_position DESC, id DESC").Limit(1).Find(&previous).Error
+	if err != nil {
+		return err
+	}
+	err = db.Where("series_id = ? AND (series_position > ? OR (series_position = ? AND id > ?))", series, position, position, book.ID).
+		Order("series_position, id").Limit(1).Find(&next).Error
+	if err != nil {
+		return err
+	}
+
+	if len(previous) > 0 {
+		book.PreviousInSeries = &previous[0]
+	}
+	if len(next) > 0 {
+		book.NextInSeries = &next[0]
+	}
+	return nil
+}
+
+func (r *bookRepository) GetByID(id uint) (*models.Book, error) {
 	var book models.Book
-	result := database.DB.Preload("Author").Preload("Reviews").First(&book, id)
-	return &book, result.Error
+	result := withDetails(r.db).First(&book, id)
+	if result.Error == nil {
+		result.Error = loadSeriesNeighbours(r.db, &book)
+	}
+	if result.Error == nil {
+		result.Error = loadAvailability(r.db, &book)
+	}
+	return &book, translateError(result.Error, resourceBook)
 }
 
-func GetAllBooks(page, pageSize int) ([]models.Book, int64, error) {
+func (r *bookRepository) GetByISBN(isbn13 string) (*models.Book, error) {
+	var book models.Book
+	result := withDetails(r.db).Where("id IN (?)", booksWithISBNs(r.db, []string{isbn13})).First(&book)
+	if result.Error == nil {
+		result.Error = loadSeriesNeighbours(r.db, &book)
+	}
+	if result.Error == nil {
+		result.Error = loadAvailability(r.db, &book)
+	}
+	return &book, translateError(result.Error, resourceBook)
+}
+
+func (r *bookRepository) GetByISBNs(isbn13s []string) ([]models.Book, error) {
+	var books []models.Book
+	if len(isbn13s) == 0 {
+		return books, nil
+	}
+	if err := withDetails(r.db).Where("id IN (?)", booksWithISBNs(r.db, isbn13s)).Order("id").Find(&books).Error; err != nil {
+		return books, translateError(err, resourceBook)
+	}
+	for i := range books {
+		if err := loadSeriesNeighbours(r.db, &books[i]); err != nil {
+			return books, translateError(err, resourceBook)
+		}
+	}
+	return books, translateError(loadAvailability(r.db, pointers(books)...), resourceBook)
+}
+
+// pointers returns pointers to the books of a slice, to load them in place
+func pointers(books []models.Book) []*models.Book {
+	pointers := make([]*models.Book, len(books))
+	for i := range books {
+		pointers[i] = &books[i]
+	}
+	return pointers
+}
+
+// booksWithISBNs selects the books with an edition that has any of the normalized ISBNs
+func booksWithISBNs(db *gorm.DB, isbn13s []string) *gorm.DB {
+	return db.Model(&models.Edition{}).Select("book_id").Where("isbn13 IN ?", isbn13s)
+}
+
+func (r *bookRepository) GetAll(filter BookFilter, page, pageSize int) ([]models.Book, int64, error) {
 	var books []models.Book
 	var count int64
 
 	// Get total count
-	if err := database.DB.Model(&models.Book{}).Count(&count).Error; err != nil {
-		return nil, 0, err
+	if err := r.filtered(filter).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceBook)
 	}
 
 	// Get paginated books
 	offset := (page - 1) * pageSize
-	result := database.DB.Preload("Author").Offset(offset).Limit(pageSize).Find(&books)
-	return books, count, result.Error
+	result := r.filtered(filter).Preload("Author").
+		Order(orderClause("books", filter.Sort, BookSortFields)).
+		Offset(offset).Limit(pageSize).Find(&books)
+	if result.Error == nil {
+		result.Error = loadAvailability(r.db, pointers(books)...)
+	}
+	return books, count, translateError(result.Error, resourceBook)
 }
 
-func UpdateBook(book *models.Book) error {
-	return database.DB.Save(book).Error
+func (r *bookRepository) GetAllByCursor(filter BookFilter, cursor *Cursor, limit int) ([]models.Book, KeysetPage, error) {
+	keys := keysetFields(filter.Sort)
+
+	var values []any
+	if cursor != nil {
+		if err := checkCursor(cursor, filter.Sort, keys); err != nil {
+			return nil, KeysetPage{}, translateError(err, resourceBook)
+		}
+		var err error
+		values, err = cursorKeys(cursor, keys, func(field string) any { return bookSortKey(models.Book{}, field) })
+		if err != nil {
+			return nil, KeysetPage{}, translateError(err, resourceBook)
+		}
+	}
+
+	var books []models.Book
+	query := keysetQuery(r.filtered(filter).Preload("Author"), "books", keys, BookSortFields, values, cursor, limit)
+	if err := query.Find(&books).Error; err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceBook)
+	}
+
+	count, page := keysetPage(len(books), limit, filter.Sort, cursor, func(i int) []string {
+		return bookCursorValues(books[i], keys)
+	})
+	books = books[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(books)
+	}
+	if err := loadAvailability(r.db, pointers(books)...); err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceBook)
+	}
+	return books, page, nil
 }
 
-func DeleteBook(id uint) error {
-	// Start a transaction to delete the book and its reviews
-	return database.DB.Transaction(func(tx *gorm.DB) error {
-		// Delete all reviews for this book
-		if err := tx.Where("book_id = ?", id).Delete(&models.Review{}).Error; err != nil {
+// bookCursorValues encodes the sort keys of a book for a cursor
+func bookCursorValues(book models.Book, keys []SortField) []string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = formatSortKey(bookSortKey(book, key.Field))
+	}
+	return values
+}
+
+// filtered applies the conditions of a BookFilter to a books query
+func (r *bookRepository) filtered(filter BookFilter) *gorm.DB {
+	query := r.db.Model(&models.Book{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.AuthorID != nil {
+		query = query.Where("books.author_id = ?", *filter.AuthorID)
+	}
+	if filter.YearGte != nil {
+		query = query.Where("books.publication_year >= ?", *filter.YearGte)
+	}
+	if filter.YearLte != nil {
+		query = query.Where("books.publication_year <= ?", *filter.YearLte)
+	}
+	if filter.Title != "" {
+		query = query.Where(`LOWER(books.title) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(filter.Title))+"%")
+	}
+	if filter.MinRating != nil {
+		query = query.Where("books.id IN (?)", r.db.Model(&models.Review{}).
+			Select("book_id").Group("book_id").Having("AVG(rating) >= ?", *filter.MinRating))
+	}
+	if len(filter.GenreIDs) > 0 {
+		query = query.Where("books.id IN (?)", linkedBooks(r.db.Model(&models.BookGenre{}), "genre_id", filter.GenreIDs, filter.AllGenres))
+	}
+	if len(filter.TagIDs) > 0 {
+		query = query.Where("books.id IN (?)", linkedBooks(r.db.Model(&models.BookTag{}), "tag_id", filter.TagIDs, filter.AllTags))
+	}
+	if filter.SubjectPath != "" {
+		subtree := r.db.Model(&models.Subject{}).Select("id").Where("path LIKE ?", filter.SubjectPath+"%")
+		query = query.Where("books.id IN (?)", r.db.Model(&models.BookSubject{}).Select("book_id").Where("subject_id IN (?)", subtree))
+	}
+	return query
+}
+
+// linkedBooks selects the books of a join table linked to any of ids, or to
+// all of them when all is set. The IDs must be distinct.
+func linkedBooks(links *gorm.DB, column string, ids []uint, all bool) *gorm.DB {
+	query := links.Select("book_id").Where(column+" IN ?", ids)
+	if all {
+		query = query.Group("book_id").Having("COUNT(*) = ?", len(ids))
+	}
+	return query
+}
+
+func (r *bookRepository) Update(book *models.Book) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, book, &book.Version, resourceBook); err != nil {
+			return err
+		}
+		if err := saveFirstEdition(tx, book); err != nil {
+			return err
+		}
+		if err := replaceContributors(tx, book); err != nil {
+			return err
+		}
+		if err := replaceClassification(tx, book); err != nil {
+			return err
+		}
+		if err := loadSeriesNeighbours(tx, book); err != nil {
+			return err
+		}
+		return loadAvailability(tx, book)
+	})
+	return r.translateBookError(err, *book)
+}
+
+func (r *bookRepository) Delete(id uint) error {
+	// Start a transaction to delete the book, its reviews, editions and copies
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteBooks(tx, "id = ?", id)
+	})
+	return translateError(err, resourceBook)
+}
+
+// deleteBooks soft deletes the live books matching a condition together with
+// their reviews, editions and copies. They share the deletion time, which is
+// how Restore finds the records to bring back.
+func deleteBooks(tx *gorm.DB, query string, args ...any) error {
+	now := time.Now()
+	books := tx.Model(&models.Book{}).Select("id").Where(query, args...)
+	for _, dependent := range []any{&models.Review{}, &models.Edition{}, &models.Copy{}} {
+		if err := tx.Model(dependent).Where("book_id IN (?)", books).UpdateColumn("deleted_at", now).Error; err != nil {
 			return err
 		}
+	}
+	return tx.Model(&models.Book{}).Where(query, args...).UpdateColumn("deleted_at", now).Error
+}
 
-		// Delete the book
-		return tx.Delete(&models.Book{}, id).Error
+func (r *bookRepository) GetDeletedByID(id uint) (*models.Book, error) {
+	var book models.Book
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&book, id)
+	return &book, translateError(result.Error, resourceBook)
+}
+
+func (r *bookRepository) Restore(id uint) error {
+	restored := map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Reviews deleted on their own before the book stay deleted. The
+		// unique index on the ISBNs of editions fails with book_exists when
+		// another book now has one of them.
+		deletedAt := tx.Unscoped().Model(&models.Book{}).Select("deleted_at").Where("id = ?", id)
+		for _, dependent := range []any{&models.Review{}, &models.Edition{}} {
+			if err := tx.Unscoped().Model(dependent).
+				Where("book_id = ? AND deleted_at = (?)", id, deletedAt).
+				UpdateColumns(restored).Error; err != nil {
+				return err
+			}
+		}
+		// The barcode of a copy may have been given to another copy in the meantime
+		if err := tx.Unscoped().Model(&models.Copy{}).
+			Where("book_id = ? AND deleted_at = (?)", id, deletedAt).
+			UpdateColumns(restored).Error; err != nil {
+			return translateError(err, resourceCopy)
+		}
+
+		result := tx.Unscoped().Model(&models.Book{}).Where("id = ? AND deleted_at IS NOT NULL", id).UpdateColumns(restored)
+		if result.Error == nil && result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return result.Error
 	})
+	// Another book may have taken the book's place in its series
+	var book models.Book
+	if errors.Is(err, gorm.ErrDuplicatedKey) && r.db.Unscoped().First(&book, id).Error == nil {
+		return r.translateBookError(err, book)
+	}
+	return translateError(err, resourceBook)
 }
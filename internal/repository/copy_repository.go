@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+type copyRepository struct {
+	db *gorm.DB
+}
+
+// NewCopyRepository returns a GORM-backed CopyRepository
+func NewCopyRepository(db *gorm.DB) CopyRepository {
+	return &copyRepository{db: db}
+}
+
+func (r *copyRepository) Create(bookCopy *models.Copy) error {
+	return translateError(r.db.Create(bookCopy).Error, resourceCopy)
+}
+
+func (r *copyRepository) GetByID(id uint) (*models.Copy, error) {
+	var bookCopy models.Copy
+	result := r.db.First(&bookCopy, id)
+	return &bookCopy, translateError(result.Error, resourceCopy)
+}
+
+func (r *copyRepository) GetByBookID(bookID uint, status string) ([]models.Copy, error) {
+	var copies []models.Copy
+	query := r.db.Where("book_id = ?", bookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("id").Find(&copies)
+	return copies, translateError(result.Error, resourceCopy)
+}
+
+func (r *copyRepository) Update(bookCopy *models.Copy) error {
+	return updateVersioned(r.db, bookCopy, &bookCopy.Version, resourceCopy)
+}
+
+func (r *copyRepository) Delete(id uint) error {
+	return translateError(r.db.Unscoped().Delete(&models.Copy{}, id).Error, resourceCopy)
+}
+
+// loadAvailability counts the copies of books by status
+func loadAvailability(db *gorm.DB, books ...*models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint]*models.Book, len(books))
+	for _, book := range books {
+		book.Availability = &models.Availability{}
+		byID[book.ID] = book
+	}
+
+	var rows []struct {
+		BookID    uint
+		Status    string
+		CopyCount int64
+	}
+	err := db.Model(&models.Copy{}).
+		Select("book_id, status, COUNT(*) AS copy_count").
+		Where("book_id IN ?", slices.Collect(maps.Keys(byID))).
+		Group("book_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		byID[row.BookID].Availability.Add(row.Status, row.CopyCount)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or do not match the listing
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the boundary row of a keyset-paginated listing. Values holds
+// the row's sort key values followed by its ID, encoded as strings.
+type Cursor struct {
+	Sort     string   `json:"s,omitempty"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// KeysetPage holds the cursors around a page. A nil cursor means there is no page in that direction.
+type KeysetPage struct {
+	Next *Cursor
+	Prev *Cursor
+}
+
+// Encode returns the opaque token handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// SortSpec renders sort fields back into the sort parameter syntax
+func SortSpec(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Authors and reviews are paginated by ID only
+var (
+	idKeys    = []SortField{{Field: "id"}}
+	idColumns = map[string]string{"id": "id"}
+)
+
+// idCursorKeys decodes a cursor of a listing paginated by ID
+func idCursorKeys(cursor *Cursor) ([]any, error) {
+	if cursor == nil {
+		return nil, nil
+	}
+	if err := checkCursor(cursor, nil, idKeys); err != nil {
+		return nil, err
+	}
+	return cursorKeys(cursor, idKeys, func(string) any { return uint(0) })
+}
+
+// keysetFields returns the sort fields with the id tie-breaker appended
+func keysetFields(fields []SortField) []SortField {
+	for _, field := range fields {
+		if field.Field == "id" {
+			return fields
+		}
+	}
+	return append(append([]SortField{}, fields...), SortField{Field: "id"})
+}
+
+// checkCursor verifies that a cursor was issued for the same sort order
+func checkCursor(cursor *Cursor, sort []SortField, keys []SortField) error {
+	if cursor.Sort != SortSpec(sort) || len(cursor.Values) != len(keys) {
+		return fmt.Errorf("%w: it was issued for a different sort order", ErrInvalidCursor)
+	}
+	return nil
+}
+
+// keysetCondition builds the WHERE clause selecting the rows after the
+// boundary row in the order given by keys, or before it when backward is set
+func keysetCondition(table string, keys []SortField, columns map[string]string, values []any, backward bool) (string, []any) {
+	var clauses []string
+	var args []any
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s.%s = ?", table, columns[keys[j].Field]))
+			args = append(args, values[j])
+		}
+
+		operator := ">"
+		if key.Desc != backward {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s.%s %s ?", table, columns[key.Field], operator))
+		args = append(args, values[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// reverseFields flips the direction of every sort field
+func reverseFields(fields []SortField) []SortField {
+	reversed := make([]SortField, len(fields))
+	for i, field := range fields {
+		reversed[i] = SortField{Field: field.Field, Desc: !field.Desc}
+	}
+	return reversed
+}
+
+// keysetQuery applies the cursor condition, order and limit to query. It asks
+// for one extra row so the caller can tell whether more rows follow.
+func keysetQuery(query *gorm.DB, table string, keys []SortField, columns map[string]string, values []any, cursor *Cursor, limit int) *gorm.DB {
+	order := keys
+	if cursor != nil {
+		condition, args := keysetCondition(table, keys, columns, values, cursor.Backward)
+		query = query.Where(condition, args...)
+		if cursor.Backward {
+			order = reverseFields(keys)
+		}
+	}
+	return query.Order(orderClause(table, order, columns)).Limit(limit + 1)
+}
+
+// keysetPage trims the extra row fetched by keysetQuery and builds the cursors
+// around the page. rows must be in the order the query returned them; boundary
+// returns the cursor values of the row at an index of that slice.
+func keysetPage(count, limit int, sort []SortField, cursor *Cursor, boundary func(i int) []string) (int, KeysetPage) {
+	hasMore := count > limit
+	if hasMore {
+		count = limit
+	}
+
+	var page KeysetPage
+	if count == 0 {
+		return 0, page
+	}
+
+	backward := cursor != nil && cursor.Backward
+	first, last := 0, count-1
+	if backward {
+		// Rows came back in reverse order, the caller flips them
+		first, last = count-1, 0
+	}
+
+	spec := SortSpec(sort)
+	if hasMore || backward {
+		page.Next = &Cursor{Sort: spec, Values: boundary(last)}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		page.Prev = &Cursor{Sort: spec, Values: boundary(first), Backward: true}
+	}
+	return count, page
+}
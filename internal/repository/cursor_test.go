@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"errors"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	valid := Cursor{Sort: "-publication_year", Values: []string{"1999", "7"}, Backward: true}
+
+	tests := []struct {
+		name  string
+		token string
+		want  *Cursor
+	}{
+		{"round trip", valid.Encode(), &valid},
+		{"empty", "", nil},
+		{"not base64", "not a cursor!", nil},
+		{"not json", "bm90IGpzb24", nil},
+		{"no values", Cursor{Sort: "title"}.Encode(), nil},
+		{"padded base64", valid.Encode() + "==", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.token)
+			if tt.want == nil {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("DecodeCursor(%q) = %+v, %v, want ErrInvalidCursor", tt.token, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q): %v", tt.token, err)
+			}
+			if got.Sort != tt.want.Sort || got.Backward != tt.want.Backward || !slices.Equal(got.Values, tt.want.Values) {
+				t.Errorf("DecodeCursor(%q) = %+v, want %+v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+// bookPage is one page of book IDs with the cursors around it
+type bookPage struct {
+	ids  []uint
+	page KeysetPage
+}
+
+// listBooks fetches one page of books, failing the test on errors
+func listBooks(t *testing.T, b backend, sort []SortField, cursor *Cursor, limit int) bookPage {
+	t.Helper()
+	books, page, err := b.books.GetAllByCursor(BookFilter{Sort: sort}, cursor, limit)
+	if err != nil {
+		t.Fatalf("GetAllByCursor(%+v): %v", cursor, err)
+	}
+	ids := make([]uint, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	return bookPage{ids, page}
+}
+
+// roundTrip passes a cursor through its token, as clients do
+func roundTrip(t *testing.T, cursor *Cursor) *Cursor {
+	t.Helper()
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("decode %+v: %v", cursor, err)
+	}
+	return decoded
+}
+
+func TestBookCursorPagination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		author := createAuthor(t, b, "Author")
+		// Years repeat so that pages break inside runs of equal sort keys
+		years := []int{2001, 1999, 2001, 2000, 2001, 1999, 2001}
+		for i, year := range years {
+			createBook(t, b, author.ID, string(rune('A'+i)), year)
+		}
+
+		tests := []struct {
+			name string
+			sort []SortField
+			want []uint
+		}{
+			{"by id", nil, []uint{1, 2, 3, 4, 5, 6, 7}},
+			{"ties broken by id", []SortField{{Field: "publication_year"}}, []uint{2, 6, 4, 1, 3, 5, 7}},
+			{"descending, ties still ascending by id", []SortField{{Field: "publication_year", Desc: true}}, []uint{1, 3, 5, 7, 4, 2, 6}},
+			{"two fields", []SortField{{Field: "publication_year", Desc: true}, {Field: "title", Desc: true}}, []uint{7, 5, 3, 1, 4, 6, 2}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Forward through every page
+				var forward [][]uint
+				var pages []bookPage
+				var cursor *Cursor
+				for {
+					if len(pages) > len(tt.want) {
+						t.Fatalf("paging does not end, got %v", forward)
+					}
+					page := listBooks(t, b, tt.sort, cursor, 3)
+					if cursor == nil && page.page.Prev != nil {
+						t.Errorf("first page has a previous cursor")
+					}
+					forward = append(forward, page.ids)
+					pages = append(pages, page)
+					if page.page.Next == nil {
+						break
+					}
+					cursor = roundTrip(t, page.page.Next)
+				}
+				if got := slices.Concat(forward...); !slices.Equal(got, tt.want) {
+					t.Fatalf("forward pages %v, want %v", forward, tt.want)
+				}
+				if len(forward) != 3 || len(forward[2]) != 1 {
+					t.Fatalf("forward pages %v, want pages of 3, 3 and 1", forward)
+				}
+
+				// Backward from the last page gives the same pages
+				for i := len(pages) - 1; i > 0; i-- {
+					if pages[i].page.Prev == nil {
+						t.Fatalf("page %d has no previous cursor", i)
+					}
+					back := listBooks(t, b, tt.sort, roundTrip(t, pages[i].page.Prev), 3)
+					if !slices.Equal(back.ids, forward[i-1]) {
+						t.Errorf("page before %v is %v, want %v", forward[i], back.ids, forward[i-1])
+					}
+					if back.page.Next == nil {
+						t.Errorf("page %v reached backward has no next cursor", back.ids)
+					}
+					if (i-1 == 0) != (back.page.Prev == nil) {
+						t.Errorf("page %v reached backward has previous cursor %+v", back.ids, back.page.Prev)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBookCursorRejected(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		author := createAuthor(t, b, "Author")
+		for i := range 3 {
+			createBook(t, b, author.ID, string(rune('A'+i)), 2000+i)
+		}
+		byYear := []SortField{{Field: "publication_year"}}
+		next := listBooks(t, b, byYear, nil, 1).page.Next
+
+		tests := []struct {
+			name   string
+			sort   []SortField
+			cursor *Cursor
+		}{
+			{"other sort field", []SortField{{Field: "title"}}, next},
+			{"other direction", []SortField{{Field: "publication_year", Desc: true}}, next},
+			{"sort left out", nil, next},
+			{"values missing", byYear, &Cursor{Sort: next.Sort, Values: next.Values[:1]}},
+			{"extra values", byYear, &Cursor{Sort: next.Sort, Values: append(slices.Clone(next.Values), "1")}},
+			{"value of the wrong type", byYear, &Cursor{Sort: next.Sort, Values: []string{"year", next.Values[1]}}},
+			{"id not a number", byYear, &Cursor{Sort: next.Sort, Values: []string{next.Values[0], "-1"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				books, _, err := b.books.GetAllByCursor(BookFilter{Sort: tt.sort}, tt.cursor, 1)
+				if code := apperr.From(err).Code; code != "invalid_cursor" {
+					t.Errorf("GetAllByCursor = %v, %v, want invalid_cursor", bookIDs(books), err)
+				}
+			})
+		}
+	})
+}
+
+func TestAuthorCursorPagination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		for _, name := range []string{"A", "B", "C", "D", "E"} {
+			createAuthor(t, b, name)
+		}
+
+		var ids []uint
+		var cursor *Cursor
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatalf("paging does not end, got %v", ids)
+			}
+			authors, page, err := b.authors.GetAllByCursor(AuthorFilter{}, cursor, 2)
+			if err != nil {
+				t.Fatalf("GetAllByCursor: %v", err)
+			}
+			for _, author := range authors {
+				ids = append(ids, author.ID)
+			}
+			if page.Next == nil {
+				break
+			}
+			cursor = roundTrip(t, page.Next)
+		}
+		if want := []uint{1, 2, 3, 4, 5}; !slices.Equal(ids, want) {
+			t.Errorf("authors %v, want %v", ids, want)
+		}
+
+		// A book cursor names a sort, authors are only paginated by ID
+		_, _, err := b.authors.GetAllByCursor(AuthorFilter{}, &Cursor{Sort: "title", Values: []string{"x", "1"}}, 2)
+		if code := apperr.From(err).Code; code != "invalid_cursor" {
+			t.Errorf("GetAllByCursor with a book cursor = %v, want invalid_cursor", err)
+		}
+	})
+}
+
+// bookIDs lists the IDs of books, for failure messages
+func bookIDs(books []models.Book) []uint {
+	ids := make([]uint, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	return ids
+}
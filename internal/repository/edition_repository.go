@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type editionRepository struct {
+	db *gorm.DB
+}
+
+// NewEditionRepository returns a GORM-backed EditionRepository
+func NewEditionRepository(db *gorm.DB) EditionRepository {
+	return &editionRepository{db: db}
+}
+
+func (r *editionRepository) Create(edition *models.Edition) error {
+	return translateError(r.db.Omit("Publisher").Create(edition).Error, resourceEdition)
+}
+
+func (r *editionRepository) GetByID(id uint) (*models.Edition, error) {
+	var edition models.Edition
+	result := r.db.Preload("Publisher").First(&edition, id)
+	return &edition, translateError(result.Error, resourceEdition)
+}
+
+func (r *editionRepository) GetByBookID(bookID uint) ([]models.Edition, error) {
+	var editions []models.Edition
+	result := r.db.Preload("Publisher").Where("book_id = ?", bookID).Order("id").Find(&editions)
+	return editions, translateError(result.Error, resourceEdition)
+}
+
+// firstEditionID returns the ID of the first edition of a book, 0 if it has none
+func firstEditionID(db *gorm.DB, bookID uint) (uint, error) {
+	var ids []uint
+	err := db.Model(&models.Edition{}).Where("book_id = ?", bookID).Order("id").Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (r *editionRepository) Update(edition *models.Edition) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, edition, &edition.Version, resourceEdition); err != nil {
+			return err
+		}
+
+		first, err := firstEditionID(tx, edition.BookID)
+		if err != nil || first != edition.ID {
+			return err
+		}
+		// The book shows the ISBN of its first edition
+		return tx.Model(&models.Book{}).Where("id = ? AND isbn <> ?", edition.BookID, edition.ISBN).
+			UpdateColumns(map[string]any{
+				"isbn":       edition.ISBN,
+				"isbn13":     edition.ISBN13,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error
+	})
+	return translateError(err, resourceEdition)
+}
+
+func (r *editionRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var editions []models.Edition
+		if err := tx.Where("id = ?", id).Find(&editions).Error; err != nil || len(editions) == 0 {
+			return err
+		}
+		edition := editions[0]
+		first, err := firstEditionID(tx, edition.BookID)
+		if err != nil {
+			return err
+		}
+		if first == edition.ID {
+			return errInUse(resourceEdition)
+		}
+		// Editions are not restored on their own, only with their book
+		return tx.Unscoped().Delete(&edition).Error
+	})
+	return translateError(err, resourceEdition)
+}
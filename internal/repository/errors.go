@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"go-rest-api/internal/apperr"
+
+	"gorm.io/gorm"
+)
+
+// Resources named in error codes, e.g. book_not_found
+const (
+	resourceBook      = "book"
+	resourceAuthor    = "author"
+	resourceReview    = "review"
+	resourceUser      = "user"
+	resourceAPIKey    = "api_key"
+	resourceGenre     = "genre"
+	resourceTag       = "tag"
+	resourceSubject   = "subject"
+	resourceSeries    = "series"
+	resourceEdition   = "edition"
+	resourcePublisher = "publisher"
+	resourceCopy      = "copy"
+)
+
+// resourceNames are the human readable names used in error messages
+var resourceNames = map[string]string{
+	resourceBook:      "Book",
+	resourceAuthor:    "Author",
+	resourceReview:    "Review",
+	resourceUser:      "User",
+	resourceAPIKey:    "API key",
+	resourceGenre:     "Genre",
+	resourceTag:       "Tag",
+	resourceSubject:   "Subject",
+	resourceSeries:    "Series",
+	resourceEdition:   "Edition",
+	resourcePublisher: "Publisher",
+	resourceCopy:      "Copy",
+}
+
+// translateError maps GORM and repository errors to apperr errors about
+// resource. apperr errors pass through and anything unexpected becomes an
+// internal error.
+func translateError(err error, resource string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, new(*apperr.Error)):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errNotFound(resource)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return errDuplicate(resource)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return errInUse(resource)
+	case errors.Is(err, ErrInvalidCursor):
+		return apperr.Validation("invalid_cursor", err.Error())
+	default:
+		return apperr.Internal(err)
+	}
+}
+
+func errNotFound(resource string) error {
+	return apperr.NotFound(resource+"_not_found", resourceNames[resource]+" not found")
+}
+
+// errInUse reports a record that cannot be deleted while other records refer to it
+func errInUse(resource string) error {
+	return apperr.Conflict(resource+"_in_use", resourceNames[resource]+" is referenced by other records")
+}
+
+// errModified reports a versioned update that lost a race with another request
+func errModified(resource string) error {
+	return apperr.Conflict(resource+"_modified", resourceNames[resource]+" was modified by another request")
+}
+
+// errSubjectCycle reports a move of a subject under itself or one of its descendants
+func errSubjectCycle() error {
+	return apperr.Validation("subject_cycle", "A subject cannot be moved under itself or one of its descendants",
+		apperr.FieldError{Field: "parent_id", Rule: "not_descendant", Message: "must not be the subject or one of its descendants"})
+}
+
+// errPositionTaken reports a book at the position of another book of its series
+func errPositionTaken() error {
+	return apperr.Conflict("book_position_taken", "Book has the position of another book in its series")
+}
+
+func errDuplicate(resource string) error {
+	return apperr.Conflict(resource+"_exists", resourceNames[resource]+" already exists")
+}
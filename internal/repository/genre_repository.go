@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type genreRepository struct {
+	db *gorm.DB
+}
+
+// NewGenreRepository returns a GORM-backed GenreRepository
+func NewGenreRepository(db *gorm.DB) GenreRepository {
+	return &genreRepository{db: db}
+}
+
+func (r *genreRepository) Create(genre *models.Genre) error {
+	return translateError(r.db.Create(genre).Error, resourceGenre)
+}
+
+func (r *genreRepository) GetByID(id uint) (*models.Genre, error) {
+	var genre models.Genre
+	result := r.db.First(&genre, id)
+	return &genre, translateError(result.Error, resourceGenre)
+}
+
+func (r *genreRepository) GetByIDs(ids []uint) ([]models.Genre, error) {
+	var genres []models.Genre
+	if len(ids) == 0 {
+		return genres, nil
+	}
+	result := r.db.Where("id IN ?", ids).Order("LOWER(name), id").Find(&genres)
+	return genres, translateError(result.Error, resourceGenre)
+}
+
+func (r *genreRepository) GetAll(page, pageSize int) ([]models.Genre, int64, error) {
+	var genres []models.Genre
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.Genre{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceGenre)
+	}
+
+	// Get paginated genres
+	offset := (page - 1) * pageSize
+	result := r.db.Order("LOWER(name), id").Offset(offset).Limit(pageSize).Find(&genres)
+	return genres, count, translateError(result.Error, resourceGenre)
+}
+
+func (r *genreRepository) CountBooks(genreIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		GenreID   uint
+		BookCount int64
+	}
+	counts := make(map[uint]int64, len(genreIDs))
+	if len(genreIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.Model(&models.BookGenre{}).
+		Select("genre_id, COUNT(*) AS book_count").
+		Where("genre_id IN ? AND book_id IN (?)", genreIDs, liveBookIDs(r.db)).
+		Group("genre_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err, resourceGenre)
+	}
+
+	for _, row := range rows {
+		counts[row.GenreID] = row.BookCount
+	}
+	return counts, nil
+}
+
+func (r *genreRepository) Update(genre *models.Genre) error {
+	return updateVersioned(r.db, genre, &genre.Version, resourceGenre)
+}
+
+func (r *genreRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Links of deleted books go too, restoring a book does not bring the genre back
+		if err := tx.Where("genre_id = ?", id).Delete(&models.BookGenre{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Genre{}, id).Error
+	})
+	return translateError(err, resourceGenre)
+}
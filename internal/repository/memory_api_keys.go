@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"sort"
+	"time"
+)
+
+type memoryAPIKeyRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryAPIKeyRepository) Create(key *models.APIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the unique index on api_keys.key_hash
+	for _, existing := range r.store.apiKeys {
+		if existing.KeyHash == key.KeyHash {
+			return errDuplicate(resourceAPIKey)
+		}
+	}
+
+	r.store.nextAPIKeyID++
+	now := time.Now()
+	key.ID = r.store.nextAPIKeyID
+	key.CreatedAt = now
+	key.UpdatedAt = now
+	r.store.apiKeys[key.ID] = *key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok {
+		return &models.APIKey{}, errNotFound(resourceAPIKey)
+	}
+	return &key, nil
+}
+
+func (r *memoryAPIKeyRepository) GetByHash(hash string) (*models.APIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, key := range r.store.apiKeys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return &models.APIKey{}, errNotFound(resourceAPIKey)
+}
+
+func (r *memoryAPIKeyRepository) GetAll(page, pageSize int) ([]models.APIKey, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := make([]models.APIKey, 0, len(r.store.apiKeys))
+	for _, key := range r.store.apiKeys {
+		all = append(all, key)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryAPIKeyRepository) Update(key *models.APIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key.UpdatedAt = time.Now()
+	r.store.apiKeys[key.ID] = *key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if key, ok := r.store.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.store.apiKeys[id] = key
+	}
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.apiKeys, id)
+	return nil
+}
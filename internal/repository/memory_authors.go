@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// booksByAuthor returns the stored books of an author ordered by ID. Callers must hold the lock.
+func (s *MemoryStore) booksByAuthor(authorID uint) []models.Book {
+	books := []models.Book{}
+	for _, book := range s.books {
+		if book.AuthorID == authorID {
+			books = append(books, book)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books
+}
+
+// creditedBooks returns the stored books that credit an author in any role,
+// ordered by ID. Callers must hold the lock.
+func (s *MemoryStore) creditedBooks(authorID uint) []models.Book {
+	books := []models.Book{}
+	for _, book := range s.books {
+		if slices.ContainsFunc(book.Contributors, func(c models.BookContributor) bool { return c.AuthorID == authorID }) {
+			books = append(books, book)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books
+}
+
+// contributions returns the credits of an author on stored books, with the
+// book, ordered by book and position. Callers must hold the lock.
+func (s *MemoryStore) contributions(authorID uint) []models.BookContributor {
+	contributions := []models.BookContributor{}
+	for _, book := range s.creditedBooks(authorID) {
+		for _, contributor := range book.Contributors {
+			if contributor.AuthorID == authorID {
+				contributor.Book = book
+				contributor.Book.Contributors = nil
+				contributions = append(contributions, contributor)
+			}
+		}
+	}
+	return contributions
+}
+
+type memoryAuthorRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryAuthorRepository) Create(author *models.Author) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextAuthorID++
+	now := time.Now()
+	author.ID = r.store.nextAuthorID
+	author.CreatedAt = now
+	author.UpdatedAt = now
+	author.Version = 1
+
+	stored := *author
+	stored.Books = nil
+	r.store.authors[author.ID] = stored
+	return nil
+}
+
+func (r *memoryAuthorRepository) GetByID(id uint) (*models.Author, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	author, ok := r.store.authors[id]
+	if !ok {
+		return &models.Author{}, errNotFound(resourceAuthor)
+	}
+	author.Books = r.store.booksByAuthor(id)
+	author.Contributions = r.store.contributions(id)
+	return &author, nil
+}
+
+func (r *memoryAuthorRepository) GetAll(filter AuthorFilter, page, pageSize int) ([]models.Author, int64, error) {
+	all := r.sorted(filter)
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryAuthorRepository) GetAllByCursor(filter AuthorFilter, cursor *Cursor, limit int) ([]models.Author, KeysetPage, error) {
+	values, err := idCursorKeys(cursor)
+	if err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceAuthor)
+	}
+
+	all := r.sorted(filter)
+	indexes := memoryKeyset(len(all), cursor, limit, func(i int) int {
+		return compareToCursor(idKeys, values, func(string) any { return all[i].ID })
+	})
+	authors := make([]models.Author, len(indexes))
+	for i, index := range indexes {
+		authors[i] = all[index]
+	}
+
+	count, page := keysetPage(len(authors), limit, nil, cursor, func(i int) []string {
+		return []string{formatSortKey(authors[i].ID)}
+	})
+	authors = authors[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(authors)
+	}
+	return authors, page, nil
+}
+
+func (r *memoryAuthorRepository) CountBooks(authorIDs []uint) (map[uint]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64, len(authorIDs))
+	for _, id := range authorIDs {
+		counts[id] = int64(len(r.store.creditedBooks(id)))
+	}
+	return counts, nil
+}
+
+// sorted returns the authors selected by filter ordered by ID
+func (r *memoryAuthorRepository) sorted(filter AuthorFilter) []models.Author {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	authors := make([]models.Author, 0, len(r.store.authors))
+	for _, author := range withDeleted(r.store.authors, r.store.deletedAuthors, filter.IncludeDeleted) {
+		if filter.IncludeBooks {
+			author.Books = r.store.booksByAuthor(author.ID)
+		}
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].ID < authors[j].ID })
+	return authors
+}
+
+func (r *memoryAuthorRepository) Update(author *models.Author) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.authors[author.ID]; !ok || current.Version != author.Version {
+		return errModified(resourceAuthor)
+	}
+
+	author.Version++
+	author.UpdatedAt = time.Now()
+	stored := *author
+	stored.Books = nil
+	r.store.authors[author.ID] = stored
+	return nil
+}
+
+func (r *memoryAuthorRepository) Delete(id uint, deletion AuthorDeletion) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	author, ok := r.store.authors[id]
+	if !ok {
+		return nil
+	}
+
+	deletedAt := deletedNow()
+	switch {
+	case deletion.ReassignTo != nil:
+		to := *deletion.ReassignTo
+		if _, ok := r.store.authors[to]; !ok {
+			return errNotFound(resourceAuthor)
+		}
+		for _, book := range r.store.creditedBooks(id) {
+			if book.AuthorID == id {
+				book.AuthorID = to
+			}
+			// Credits the new author already has in the same role are merged into theirs
+			var contributors []models.BookContributor
+			for _, contributor := range book.Contributors {
+				if contributor.AuthorID == id {
+					contributor.AuthorID = to
+				}
+				if !slices.ContainsFunc(contributors, func(c models.BookContributor) bool {
+					return c.AuthorID == contributor.AuthorID && c.Role == contributor.Role
+				}) {
+					contributors = append(contributors, contributor)
+				}
+			}
+			book.Contributors = contributors
+			book.Version++
+			book.UpdatedAt = time.Now()
+			r.store.storeBook(&book)
+		}
+	case deletion.Cascade:
+		// Books of which the author is the primary author are deleted,
+		// on the others the author is no longer credited
+		for _, book := range r.store.booksByAuthor(id) {
+			r.store.deleteBook(book, deletedAt)
+		}
+		for _, book := range r.store.creditedBooks(id) {
+			book.Contributors = slices.DeleteFunc(book.Contributors, func(c models.BookContributor) bool { return c.AuthorID == id })
+			book.Version++
+			book.UpdatedAt = time.Now()
+			r.store.storeBook(&book)
+		}
+	}
+	if len(r.store.creditedBooks(id)) > 0 || len(r.store.booksByAuthor(id)) > 0 {
+		return errInUse(resourceAuthor)
+	}
+
+	author.DeletedAt = deletedAt
+	r.store.deletedAuthors[id] = author
+	delete(r.store.authors, id)
+	return nil
+}
+
+func (r *memoryAuthorRepository) GetDeletedByID(id uint) (*models.Author, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	author, ok := r.store.deletedAuthors[id]
+	if !ok {
+		return &models.Author{}, errNotFound(resourceAuthor)
+	}
+	return &author, nil
+}
+
+func (r *memoryAuthorRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	author, ok := r.store.deletedAuthors[id]
+	if !ok {
+		return errNotFound(resourceAuthor)
+	}
+
+	author.DeletedAt = gorm.DeletedAt{}
+	author.Version++
+	r.store.authors[id] = author
+	delete(r.store.deletedAuthors, id)
+	return nil
+}
+
+// hasBooks reports whether any book, deleted or not, refers to or credits an author. Callers must hold the lock.
+func (s *MemoryStore) hasBooks(authorID uint) bool {
+	return slices.ContainsFunc(withDeleted(s.books, s.deletedBooks, true), func(book models.Book) bool {
+		return book.AuthorID == authorID || slices.ContainsFunc(book.Contributors, func(c models.BookContributor) bool {
+			return c.AuthorID == authorID
+		})
+	})
+}
@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// storeBook saves a book without its loaded associations, numbering its
+// contributors in credit order and keeping only the IDs of its genres, tags
+// and subjects. Callers must hold the lock.
+func (s *MemoryStore) storeBook(book *models.Book) {
+	for i := range book.Contributors {
+		book.Contributors[i].BookID = book.ID
+		book.Contributors[i].Position = i
+	}
+	for i := range book.Editions {
+		book.Editions[i].BookID = book.ID
+	}
+
+	stored := *book
+	stored.Availability = nil
+	stored.Author = models.Author{}
+	stored.Reviews = nil
+	stored.Series = nil
+	stored.PreviousInSeries, stored.NextInSeries = nil, nil
+	stored.Contributors = make([]models.BookContributor, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		contributor.Author = models.Author{}
+		stored.Contributors[i] = contributor
+	}
+	stored.Editions = make([]models.Edition, len(book.Editions))
+	for i, edition := range book.Editions {
+		edition.Publisher = nil
+		stored.Editions[i] = edition
+	}
+	stored.Genres = make([]models.Genre, len(book.Genres))
+	for i, genre := range book.Genres {
+		stored.Genres[i] = models.Genre{ID: genre.ID}
+	}
+	stored.Tags = make([]models.Tag, len(book.Tags))
+	for i, tag := range book.Tags {
+		stored.Tags[i] = models.Tag{ID: tag.ID}
+	}
+	stored.Subjects = make([]models.Subject, len(book.Subjects))
+	for i, subject := range book.Subjects {
+		stored.Subjects[i] = models.Subject{ID: subject.ID}
+	}
+	s.books[book.ID] = stored
+}
+
+// bookDetails loads the author, contributors, reviews, editions, genres, tags,
+// subjects and series of a stored book, like the GORM preloads, the books
+// before and after it in its series and the counts of its copies. Callers must
+// hold the lock.
+func (s *MemoryStore) bookDetails(book models.Book) models.Book {
+	book.Author = s.authors[book.AuthorID]
+	contributors := make([]models.BookContributor, len(book.Contributors))
+	for i, contributor := range book.Contributors {
+		contributor.Author = s.authors[contributor.AuthorID]
+		contributors[i] = contributor
+	}
+	book.Contributors = contributors
+	book.Reviews = s.reviewsByBook(book.ID)
+	editions := make([]models.Edition, len(book.Editions))
+	for i, edition := range book.Editions {
+		editions[i] = s.editionDetails(edition)
+	}
+	book.Editions = editions
+
+	genres := make([]models.Genre, len(book.Genres))
+	for i, genre := range book.Genres {
+		genres[i] = s.genres[genre.ID]
+	}
+	sortByName(genres, func(genre models.Genre) (string, uint) { return genre.Name, genre.ID })
+	book.Genres = genres
+
+	tags := make([]models.Tag, len(book.Tags))
+	for i, tag := range book.Tags {
+		tags[i] = s.tags[tag.ID]
+	}
+	sortByName(tags, func(tag models.Tag) (string, uint) { return tag.Name, tag.ID })
+	book.Tags = tags
+
+	subjects := make([]models.Subject, len(book.Subjects))
+	for i, subject := range book.Subjects {
+		subjects[i] = s.subjects[subject.ID]
+	}
+	sortSubjects(subjects, func(subject models.Subject) string { return subject.Code })
+	book.Subjects = subjects
+
+	if book.SeriesID != nil {
+		series := s.series[*book.SeriesID]
+		book.Series = &series
+	}
+	s.seriesNeighbours(&book)
+	countCopies(&book)
+	return book
+}
+
+type memoryBookRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryBookRepository) Create(book *models.Book) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.positionTaken(*book) {
+		return errPositionTaken()
+	}
+	if r.store.isbnTaken(book.ISBN13, 0) {
+		return errDuplicate(resourceBook)
+	}
+
+	r.store.nextBookID++
+	now := time.Now()
+	book.ID = r.store.nextBookID
+	book.CreatedAt = now
+	book.UpdatedAt = now
+	book.Version = 1
+
+	book.Editions = nil
+	book.Copies = nil
+	r.store.saveFirstEdition(book)
+	r.store.storeBook(book)
+	book.Availability = &models.Availability{}
+	return nil
+}
+
+func (r *memoryBookRepository) GetByID(id uint) (*models.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, ok := r.store.books[id]
+	if !ok {
+		return &models.Book{}, errNotFound(resourceBook)
+	}
+	book = r.store.bookDetails(book)
+	return &book, nil
+}
+
+func (r *memoryBookRepository) GetByISBN(isbn13 string) (*models.Book, error) {
+	books, _ := r.GetByISBNs([]string{isbn13})
+	if len(books) == 0 {
+		return &models.Book{}, errNotFound(resourceBook)
+	}
+	return &books[0], nil
+}
+
+func (r *memoryBookRepository) GetByISBNs(isbn13s []string) ([]models.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	books := []models.Book{}
+	for _, book := range r.store.books {
+		if slices.ContainsFunc(book.Editions, func(edition models.Edition) bool {
+			return edition.ISBN13 != nil && slices.Contains(isbn13s, *edition.ISBN13)
+		}) {
+			books = append(books, r.store.bookDetails(book))
+		}
+	}
+	sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+	return books, nil
+}
+
+func (r *memoryBookRepository) GetAll(filter BookFilter, page, pageSize int) ([]models.Book, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := make([]models.Book, 0, len(r.store.books))
+	for _, book := range withDeleted(r.store.books, r.store.deletedBooks, filter.IncludeDeleted) {
+		if r.store.matchesBookFilter(book, filter) {
+			all = append(all, book)
+		}
+	}
+	sortBooks(all, filter.Sort)
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+
+	books := all[offset:end]
+	for i := range books {
+		books[i].Author = r.store.authors[books[i].AuthorID]
+		countCopies(&books[i])
+	}
+	return books, count, nil
+}
+
+func (r *memoryBookRepository) GetAllByCursor(filter BookFilter, cursor *Cursor, limit int) ([]models.Book, KeysetPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keys := keysetFields(filter.Sort)
+
+	var values []any
+	if cursor != nil {
+		if err := checkCursor(cursor, filter.Sort, keys); err != nil {
+			return nil, KeysetPage{}, translateError(err, resourceBook)
+		}
+		var err error
+		values, err = cursorKeys(cursor, keys, func(field string) any { return bookSortKey(models.Book{}, field) })
+		if err != nil {
+			return nil, KeysetPage{}, translateError(err, resourceBook)
+		}
+	}
+
+	all := make([]models.Book, 0, len(r.store.books))
+	for _, book := range withDeleted(r.store.books, r.store.deletedBooks, filter.IncludeDeleted) {
+		if r.store.matchesBookFilter(book, filter) {
+			all = append(all, book)
+		}
+	}
+	sortBooks(all, keys)
+
+	indexes := memoryKeyset(len(all), cursor, limit, func(i int) int {
+		return compareToCursor(keys, values, func(field string) any { return bookSortKey(all[i], field) })
+	})
+	books := make([]models.Book, len(indexes))
+	for i, index := range indexes {
+		books[i] = all[index]
+		books[i].Author = r.store.authors[books[i].AuthorID]
+		countCopies(&books[i])
+	}
+
+	count, page := keysetPage(len(books), limit, filter.Sort, cursor, func(i int) []string {
+		return bookCursorValues(books[i], keys)
+	})
+	books = books[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(books)
+	}
+	return books, page, nil
+}
+
+// matchesBookFilter reports whether a book passes every condition of filter. Callers must hold the lock.
+func (s *MemoryStore) matchesBookFilter(book models.Book, filter BookFilter) bool {
+	if filter.AuthorID != nil && book.AuthorID != *filter.AuthorID {
+		return false
+	}
+	if filter.YearGte != nil && book.PublicationYear < *filter.YearGte {
+		return false
+	}
+	if filter.YearLte != nil && book.PublicationYear > *filter.YearLte {
+		return false
+	}
+	if filter.Title != "" && !strings.Contains(strings.ToLower(book.Title), strings.ToLower(filter.Title)) {
+		return false
+	}
+	if filter.MinRating != nil {
+		reviews := s.reviewsByBook(book.ID)
+		if len(reviews) == 0 {
+			return false
+		}
+		sum := 0
+		for _, review := range reviews {
+			sum += review.Rating
+		}
+		if float64(sum)/float64(len(reviews)) < *filter.MinRating {
+			return false
+		}
+	}
+	if len(filter.GenreIDs) > 0 {
+		genreIDs := make([]uint, len(book.Genres))
+		for i, genre := range book.Genres {
+			genreIDs[i] = genre.ID
+		}
+		if !linksMatch(genreIDs, filter.GenreIDs, filter.AllGenres) {
+			return false
+		}
+	}
+	if len(filter.TagIDs) > 0 {
+		tagIDs := make([]uint, len(book.Tags))
+		for i, tag := range book.Tags {
+			tagIDs[i] = tag.ID
+		}
+		if !linksMatch(tagIDs, filter.TagIDs, filter.AllTags) {
+			return false
+		}
+	}
+	if filter.SubjectPath != "" && !slices.ContainsFunc(book.Subjects, func(subject models.Subject) bool {
+		return strings.HasPrefix(s.subjects[subject.ID].Path, filter.SubjectPath)
+	}) {
+		return false
+	}
+	return true
+}
+
+// linksMatch reports whether linked contains any of ids, or all of them when all is set
+func linksMatch(linked, ids []uint, all bool) bool {
+	for _, id := range ids {
+		found := slices.Contains(linked, id)
+		if found && !all {
+			return true
+		}
+		if !found && all {
+			return false
+		}
+	}
+	return all
+}
+
+// sortBooks orders books by the given fields, falling back to ID like orderClause
+func sortBooks(books []models.Book, fields []SortField) {
+	sort.SliceStable(books, func(i, j int) bool {
+		for _, field := range fields {
+			c := compareBooks(books[i], books[j], field.Field)
+			if c == 0 {
+				continue
+			}
+			if field.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return books[i].ID < books[j].ID
+	})
+}
+
+// compareBooks compares two books on one of the BookSortFields
+func compareBooks(a, b models.Book, field string) int {
+	return compareSortKeys(bookSortKey(a, field), bookSortKey(b, field))
+}
+
+func (r *memoryBookRepository) Update(book *models.Book) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	current, ok := r.store.books[book.ID]
+	if !ok || current.Version != book.Version {
+		return errModified(resourceBook)
+	}
+	if r.store.positionTaken(*book) {
+		return errPositionTaken()
+	}
+	var firstEditionID uint
+	if len(current.Editions) > 0 {
+		firstEditionID = current.Editions[0].ID
+	}
+	if r.store.isbnTaken(book.ISBN13, firstEditionID) {
+		return errDuplicate(resourceBook)
+	}
+
+	book.Version++
+	book.UpdatedAt = time.Now()
+	// Editions are changed through the EditionRepository, only the ISBN of
+	// the first one follows the book
+	book.Editions = slices.Clone(current.Editions)
+	book.Copies = current.Copies
+	r.store.saveFirstEdition(book)
+	r.store.storeBook(book)
+	for i, edition := range book.Editions {
+		book.Editions[i] = r.store.editionDetails(edition)
+	}
+	r.store.seriesNeighbours(book)
+	countCopies(book)
+	return nil
+}
+
+func (r *memoryBookRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if book, ok := r.store.books[id]; ok {
+		r.store.deleteBook(book, deletedNow())
+	}
+	return nil
+}
+
+// deleteBook soft deletes a book together with its reviews, at the same time.
+// Callers must hold the lock.
+func (s *MemoryStore) deleteBook(book models.Book, deletedAt gorm.DeletedAt) {
+	for reviewID, review := range s.reviews {
+		if review.BookID == book.ID {
+			review.DeletedAt = deletedAt
+			s.deletedReviews[reviewID] = review
+			delete(s.reviews, reviewID)
+		}
+	}
+	book.DeletedAt = deletedAt
+	s.deletedBooks[book.ID] = book
+	delete(s.books, book.ID)
+}
+
+func (r *memoryBookRepository) GetDeletedByID(id uint) (*models.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, ok := r.store.deletedBooks[id]
+	if !ok {
+		return &models.Book{}, errNotFound(resourceBook)
+	}
+	return &book, nil
+}
+
+func (r *memoryBookRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	book, ok := r.store.deletedBooks[id]
+	if !ok {
+		return errNotFound(resourceBook)
+	}
+	// Mirror the unique indexes, which only cover books and editions that are not deleted
+	if r.store.positionTaken(book) {
+		return errPositionTaken()
+	}
+	for _, edition := range book.Editions {
+		if r.store.isbnTaken(edition.ISBN13, edition.ID) {
+			return errDuplicate(resourceBook)
+		}
+	}
+	for _, bookCopy := range book.Copies {
+		if r.store.barcodeTaken(bookCopy.Barcode, bookCopy.ID) {
+			return errDuplicate(resourceCopy)
+		}
+	}
+
+	// Reviews deleted on their own before the book stay deleted
+	for reviewID, review := range r.store.deletedReviews {
+		if review.BookID == id && review.DeletedAt.Time.Equal(book.DeletedAt.Time) {
+			review.DeletedAt = gorm.DeletedAt{}
+			review.Version++
+			r.store.reviews[reviewID] = review
+			delete(r.store.deletedReviews, reviewID)
+		}
+	}
+
+	for i := range book.Editions {
+		book.Editions[i].Version++
+	}
+	book.Copies = slices.Clone(book.Copies)
+	for i := range book.Copies {
+		book.Copies[i].Version++
+	}
+	book.DeletedAt = gorm.DeletedAt{}
+	book.Version++
+	r.store.books[id] = book
+	delete(r.store.deletedBooks, id)
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"time"
+)
+
+// barcodeTaken mirrors the unique index on copies.barcode, reporting whether a
+// copy of a live book other than the given one has the barcode. Callers must
+// hold the lock.
+func (s *MemoryStore) barcodeTaken(barcode string, copyID uint) bool {
+	for _, book := range s.books {
+		for _, bookCopy := range book.Copies {
+			if bookCopy.ID != copyID && bookCopy.Barcode == barcode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findCopy returns the live book with the copy of the given ID and the index
+// of the copy in its copies. Callers must hold the lock.
+func (s *MemoryStore) findCopy(id uint) (models.Book, int, bool) {
+	for _, book := range s.books {
+		if i := slices.IndexFunc(book.Copies, func(bookCopy models.Copy) bool { return bookCopy.ID == id }); i >= 0 {
+			return book, i, true
+		}
+	}
+	return models.Book{}, 0, false
+}
+
+// countCopies replaces the copies of a stored book with their number by
+// status, like loadAvailability
+func countCopies(book *models.Book) {
+	book.Availability = &models.Availability{}
+	for _, bookCopy := range book.Copies {
+		book.Availability.Add(bookCopy.Status, 1)
+	}
+	book.Copies = nil
+}
+
+type memoryCopyRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryCopyRepository) Create(bookCopy *models.Copy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Handlers check that the book exists before adding copies to it
+	book, ok := r.store.books[bookCopy.BookID]
+	if !ok {
+		return errNotFound(resourceBook)
+	}
+	if r.store.barcodeTaken(bookCopy.Barcode, 0) {
+		return errDuplicate(resourceCopy)
+	}
+
+	r.store.nextCopyID++
+	now := time.Now()
+	bookCopy.ID = r.store.nextCopyID
+	bookCopy.CreatedAt = now
+	bookCopy.UpdatedAt = now
+	bookCopy.Version = 1
+
+	book.Copies = append(slices.Clone(book.Copies), *bookCopy)
+	r.store.books[book.ID] = book
+	return nil
+}
+
+func (r *memoryCopyRepository) GetByID(id uint) (*models.Copy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, i, ok := r.store.findCopy(id)
+	if !ok {
+		return &models.Copy{}, errNotFound(resourceCopy)
+	}
+	bookCopy := book.Copies[i]
+	return &bookCopy, nil
+}
+
+func (r *memoryCopyRepository) GetByBookID(bookID uint, status string) ([]models.Copy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	copies := slices.DeleteFunc(slices.Clone(r.store.books[bookID].Copies), func(bookCopy models.Copy) bool {
+		return status != "" && bookCopy.Status != status
+	})
+	return copies, nil
+}
+
+func (r *memoryCopyRepository) Update(bookCopy *models.Copy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	book, i, ok := r.store.findCopy(bookCopy.ID)
+	if !ok || book.Copies[i].Version != bookCopy.Version {
+		return errModified(resourceCopy)
+	}
+	if r.store.barcodeTaken(bookCopy.Barcode, bookCopy.ID) {
+		return errDuplicate(resourceCopy)
+	}
+
+	bookCopy.Version++
+	bookCopy.UpdatedAt = time.Now()
+	book.Copies = slices.Clone(book.Copies)
+	book.Copies[i] = *bookCopy
+	r.store.books[book.ID] = book
+	return nil
+}
+
+func (r *memoryCopyRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	book, i, ok := r.store.findCopy(id)
+	if !ok {
+		return nil
+	}
+	book.Copies = slices.Delete(slices.Clone(book.Copies), i, i+1)
+	r.store.books[book.ID] = book
+	return nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"time"
+)
+
+// editionDetails loads the publisher of a stored edition. Callers must hold the lock.
+func (s *MemoryStore) editionDetails(edition models.Edition) models.Edition {
+	if edition.PublisherID != nil {
+		publisher := s.publishers[*edition.PublisherID]
+		edition.Publisher = &publisher
+	}
+	return edition
+}
+
+// isbnTaken mirrors the unique index on editions.isbn13, reporting whether an
+// edition of a live book other than the given one has the normalized ISBN.
+// The first edition of every book has the book's ISBN, so this covers the
+// index on books.isbn13 too. Callers must hold the lock.
+func (s *MemoryStore) isbnTaken(isbn13 *string, editionID uint) bool {
+	if isbn13 == nil {
+		return false
+	}
+	for _, book := range s.books {
+		for _, edition := range book.Editions {
+			if edition.ID != editionID && edition.ISBN13 != nil && *edition.ISBN13 == *isbn13 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findEdition returns the live book with the edition of the given ID and the
+// index of the edition in its editions. Callers must hold the lock.
+func (s *MemoryStore) findEdition(id uint) (models.Book, int, bool) {
+	for _, book := range s.books {
+		if i := slices.IndexFunc(book.Editions, func(edition models.Edition) bool { return edition.ID == id }); i >= 0 {
+			return book, i, true
+		}
+	}
+	return models.Book{}, 0, false
+}
+
+// saveFirstEdition gives the first edition of a book the book's ISBN, creating
+// it for a new book, like the GORM repository. Callers must hold the lock.
+func (s *MemoryStore) saveFirstEdition(book *models.Book) {
+	if len(book.Editions) == 0 {
+		s.nextEditionID++
+		book.Editions = []models.Edition{{
+			ID:        s.nextEditionID,
+			CreatedAt: book.UpdatedAt,
+			UpdatedAt: book.UpdatedAt,
+			Version:   1,
+			BookID:    book.ID,
+			ISBN:      book.ISBN,
+			ISBN13:    book.ISBN13,
+		}}
+		return
+	}
+
+	first := &book.Editions[0]
+	if first.ISBN == book.ISBN {
+		return
+	}
+	first.ISBN, first.ISBN13 = book.ISBN, book.ISBN13
+	first.Version++
+	first.UpdatedAt = book.UpdatedAt
+}
+
+type memoryEditionRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryEditionRepository) Create(edition *models.Edition) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Handlers check that the book exists before adding editions to it
+	book, ok := r.store.books[edition.BookID]
+	if !ok {
+		return errNotFound(resourceBook)
+	}
+	if r.store.isbnTaken(edition.ISBN13, 0) {
+		return errDuplicate(resourceEdition)
+	}
+
+	r.store.nextEditionID++
+	now := time.Now()
+	edition.ID = r.store.nextEditionID
+	edition.CreatedAt = now
+	edition.UpdatedAt = now
+	edition.Version = 1
+
+	stored := *edition
+	stored.Publisher = nil
+	book.Editions = append(slices.Clone(book.Editions), stored)
+	r.store.books[book.ID] = book
+	return nil
+}
+
+func (r *memoryEditionRepository) GetByID(id uint) (*models.Edition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book, i, ok := r.store.findEdition(id)
+	if !ok {
+		return &models.Edition{}, errNotFound(resourceEdition)
+	}
+	edition := r.store.editionDetails(book.Editions[i])
+	return &edition, nil
+}
+
+func (r *memoryEditionRepository) GetByBookID(bookID uint) ([]models.Edition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	book := r.store.books[bookID]
+	editions := make([]models.Edition, len(book.Editions))
+	for i, edition := range book.Editions {
+		editions[i] = r.store.editionDetails(edition)
+	}
+	return editions, nil
+}
+
+func (r *memoryEditionRepository) Update(edition *models.Edition) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	book, i, ok := r.store.findEdition(edition.ID)
+	if !ok || book.Editions[i].Version != edition.Version {
+		return errModified(resourceEdition)
+	}
+	if r.store.isbnTaken(edition.ISBN13, edition.ID) {
+		return errDuplicate(resourceEdition)
+	}
+
+	edition.Version++
+	edition.UpdatedAt = time.Now()
+	stored := *edition
+	stored.Publisher = nil
+	book.Editions = slices.Clone(book.Editions)
+	book.Editions[i] = stored
+
+	// The book shows the ISBN of its first edition
+	if i == 0 && book.ISBN != edition.ISBN {
+		book.ISBN, book.ISBN13 = edition.ISBN, edition.ISBN13
+		book.Version++
+		book.UpdatedAt = edition.UpdatedAt
+	}
+	r.store.books[book.ID] = book
+	return nil
+}
+
+func (r *memoryEditionRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	book, i, ok := r.store.findEdition(id)
+	if !ok {
+		return nil
+	}
+	if i == 0 {
+		return errInUse(resourceEdition)
+	}
+
+	book.Editions = slices.Delete(slices.Clone(book.Editions), i, i+1)
+	r.store.books[book.ID] = book
+	return nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryGenreRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryGenreRepository) Create(genre *models.Genre) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(*genre) {
+		return errDuplicate(resourceGenre)
+	}
+
+	r.store.nextGenreID++
+	now := time.Now()
+	genre.ID = r.store.nextGenreID
+	genre.CreatedAt = now
+	genre.UpdatedAt = now
+	genre.Version = 1
+	r.store.genres[genre.ID] = *genre
+	return nil
+}
+
+// nameTaken mirrors the unique index on LOWER(genres.name). Callers must hold the lock.
+func (r *memoryGenreRepository) nameTaken(genre models.Genre) bool {
+	for id, other := range r.store.genres {
+		if id != genre.ID && strings.EqualFold(other.Name, genre.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryGenreRepository) GetByID(id uint) (*models.Genre, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	genre, ok := r.store.genres[id]
+	if !ok {
+		return &models.Genre{}, errNotFound(resourceGenre)
+	}
+	return &genre, nil
+}
+
+func (r *memoryGenreRepository) GetByIDs(ids []uint) ([]models.Genre, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	genres := []models.Genre{}
+	for _, id := range ids {
+		if genre, ok := r.store.genres[id]; ok && !slices.ContainsFunc(genres, func(g models.Genre) bool { return g.ID == id }) {
+			genres = append(genres, genre)
+		}
+	}
+	sortByName(genres, func(genre models.Genre) (string, uint) { return genre.Name, genre.ID })
+	return genres, nil
+}
+
+func (r *memoryGenreRepository) GetAll(page, pageSize int) ([]models.Genre, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := slices.Collect(maps.Values(r.store.genres))
+	sortByName(all, func(genre models.Genre) (string, uint) { return genre.Name, genre.ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryGenreRepository) CountBooks(genreIDs []uint) (map[uint]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64, len(genreIDs))
+	for _, book := range r.store.books {
+		for _, genre := range book.Genres {
+			if slices.Contains(genreIDs, genre.ID) {
+				counts[genre.ID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryGenreRepository) Update(genre *models.Genre) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.genres[genre.ID]; !ok || current.Version != genre.Version {
+		return errModified(resourceGenre)
+	}
+	if r.nameTaken(*genre) {
+		return errDuplicate(resourceGenre)
+	}
+
+	genre.Version++
+	genre.UpdatedAt = time.Now()
+	r.store.genres[genre.ID] = *genre
+	return nil
+}
+
+func (r *memoryGenreRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unlink := func(book models.Book) models.Book {
+		book.Genres = slices.DeleteFunc(slices.Clone(book.Genres), func(g models.Genre) bool { return g.ID == id })
+		return book
+	}
+	for bookID, book := range r.store.books {
+		r.store.books[bookID] = unlink(book)
+	}
+	for bookID, book := range r.store.deletedBooks {
+		r.store.deletedBooks[bookID] = unlink(book)
+	}
+	delete(r.store.genres, id)
+	return nil
+}
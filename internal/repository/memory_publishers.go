@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type memoryPublisherRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryPublisherRepository) Create(publisher *models.Publisher) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(*publisher) {
+		return errDuplicate(resourcePublisher)
+	}
+
+	r.store.nextPublisherID++
+	now := time.Now()
+	publisher.ID = r.store.nextPublisherID
+	publisher.CreatedAt = now
+	publisher.UpdatedAt = now
+	publisher.Version = 1
+	r.store.publishers[publisher.ID] = *publisher
+	return nil
+}
+
+// nameTaken mirrors the unique index on LOWER(publishers.name). Callers must hold the lock.
+func (r *memoryPublisherRepository) nameTaken(publisher models.Publisher) bool {
+	for id, other := range r.store.publishers {
+		if id != publisher.ID && strings.EqualFold(other.Name, publisher.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryPublisherRepository) GetByID(id uint) (*models.Publisher, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	publisher, ok := r.store.publishers[id]
+	if !ok {
+		return &models.Publisher{}, errNotFound(resourcePublisher)
+	}
+	return &publisher, nil
+}
+
+func (r *memoryPublisherRepository) GetAll(page, pageSize int) ([]models.Publisher, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := slices.Collect(maps.Values(r.store.publishers))
+	sortByName(all, func(publisher models.Publisher) (string, uint) { return publisher.Name, publisher.ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryPublisherRepository) CountEditions(publisherIDs []uint) (map[uint]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64, len(publisherIDs))
+	for _, edition := range r.editions() {
+		if slices.Contains(publisherIDs, *edition.PublisherID) {
+			counts[*edition.PublisherID]++
+		}
+	}
+	return counts, nil
+}
+
+// editions returns the editions of live books that have a publisher, ordered
+// by ID. Callers must hold the lock.
+func (r *memoryPublisherRepository) editions() []models.Edition {
+	editions := []models.Edition{}
+	for _, book := range r.store.books {
+		for _, edition := range book.Editions {
+			if edition.PublisherID != nil {
+				editions = append(editions, edition)
+			}
+		}
+	}
+	sort.Slice(editions, func(i, j int) bool { return editions[i].ID < editions[j].ID })
+	return editions
+}
+
+func (r *memoryPublisherRepository) GetEditions(publisherID uint) ([]models.Edition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	editions := slices.DeleteFunc(r.editions(), func(edition models.Edition) bool {
+		return *edition.PublisherID != publisherID
+	})
+	return editions, nil
+}
+
+func (r *memoryPublisherRepository) Update(publisher *models.Publisher) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.publishers[publisher.ID]; !ok || current.Version != publisher.Version {
+		return errModified(resourcePublisher)
+	}
+	if r.nameTaken(*publisher) {
+		return errDuplicate(resourcePublisher)
+	}
+
+	publisher.Version++
+	publisher.UpdatedAt = time.Now()
+	r.store.publishers[publisher.ID] = *publisher
+	return nil
+}
+
+func (r *memoryPublisherRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if slices.ContainsFunc(r.editions(), func(edition models.Edition) bool { return *edition.PublisherID == id }) {
+		return errInUse(resourcePublisher)
+	}
+
+	// Restoring a deleted book does not bring the publisher back
+	for bookID, book := range r.store.deletedBooks {
+		editions := slices.Clone(book.Editions)
+		for i, edition := range editions {
+			if edition.PublisherID != nil && *edition.PublisherID == id {
+				editions[i].PublisherID = nil
+			}
+		}
+		book.Editions = editions
+		r.store.deletedBooks[bookID] = book
+	}
+	delete(r.store.publishers, id)
+	return nil
+}
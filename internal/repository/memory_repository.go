@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryStore keeps authors, books, reviews, genres, tags, subjects, series, publishers, users and API keys in maps guarded by a single mutex.
+// It mirrors the behaviour of the GORM repositories, including the apperr errors
+// for missing and duplicate records, so handlers cannot tell the two backends apart.
+// Soft deleted authors, books and reviews are moved to separate maps, so that
+// reads of the live maps skip them like GORM's soft delete scope does.
+// Editions and copies are kept with their book, and so are deleted with it.
+// The repository of each resource is in the memory_<resource>.go file next to this one.
+type MemoryStore struct {
+	mu              sync.RWMutex
+	authors         map[uint]models.Author
+	books           map[uint]models.Book
+	reviews         map[uint]models.Review
+	genres          map[uint]models.Genre
+	tags            map[uint]models.Tag
+	subjects        map[uint]models.Subject
+	series          map[uint]models.Series
+	publishers      map[uint]models.Publisher
+	users           map[uint]models.User
+	apiKeys         map[uint]models.APIKey
+	deletedAuthors  map[uint]models.Author
+	deletedBooks    map[uint]models.Book
+	deletedReviews  map[uint]models.Review
+	nextAuthorID    uint
+	nextBookID      uint
+	nextReviewID    uint
+	nextGenreID     uint
+	nextTagID       uint
+	nextSubjectID   uint
+	nextSeriesID    uint
+	nextEditionID   uint
+	nextCopyID      uint
+	nextPublisherID uint
+	nextUserID      uint
+	nextAPIKeyID    uint
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		authors:    make(map[uint]models.Author),
+		books:      make(map[uint]models.Book),
+		reviews:    make(map[uint]models.Review),
+		genres:     make(map[uint]models.Genre),
+		tags:       make(map[uint]models.Tag),
+		subjects:   make(map[uint]models.Subject),
+		series:     make(map[uint]models.Series),
+		publishers: make(map[uint]models.Publisher),
+		users:      make(map[uint]models.User),
+		apiKeys:    make(map[uint]models.APIKey),
+
+		deletedAuthors: make(map[uint]models.Author),
+		deletedBooks:   make(map[uint]models.Book),
+		deletedReviews: make(map[uint]models.Review),
+	}
+}
+
+// Authors returns an AuthorRepository backed by the store
+func (s *MemoryStore) Authors() AuthorRepository {
+	return &memoryAuthorRepository{s}
+}
+
+// Books returns a BookRepository backed by the store
+func (s *MemoryStore) Books() BookRepository {
+	return &memoryBookRepository{s}
+}
+
+// Reviews returns a ReviewRepository backed by the store
+func (s *MemoryStore) Reviews() ReviewRepository {
+	return &memoryReviewRepository{s}
+}
+
+// Genres returns a GenreRepository backed by the store
+func (s *MemoryStore) Genres() GenreRepository {
+	return &memoryGenreRepository{s}
+}
+
+// Tags returns a TagRepository backed by the store
+func (s *MemoryStore) Tags() TagRepository {
+	return &memoryTagRepository{s}
+}
+
+// Subjects returns a SubjectRepository backed by the store
+func (s *MemoryStore) Subjects() SubjectRepository {
+	return &memorySubjectRepository{s}
+}
+
+// Series returns a SeriesRepository backed by the store
+func (s *MemoryStore) Series() SeriesRepository {
+	return &memorySeriesRepository{s}
+}
+
+// Editions returns an EditionRepository backed by the store
+func (s *MemoryStore) Editions() EditionRepository {
+	return &memoryEditionRepository{s}
+}
+
+// Copies returns a CopyRepository backed by the store
+func (s *MemoryStore) Copies() CopyRepository {
+	return &memoryCopyRepository{s}
+}
+
+// Publishers returns a PublisherRepository backed by the store
+func (s *MemoryStore) Publishers() PublisherRepository {
+	return &memoryPublisherRepository{s}
+}
+
+// Users returns a UserRepository backed by the store
+func (s *MemoryStore) Users() UserRepository {
+	return &memoryUserRepository{s}
+}
+
+// APIKeys returns an APIKeyRepository backed by the store
+func (s *MemoryStore) APIKeys() APIKeyRepository {
+	return &memoryAPIKeyRepository{s}
+}
+
+// Trash returns a TrashRepository backed by the store
+func (s *MemoryStore) Trash() TrashRepository {
+	return &memoryTrashRepository{s}
+}
+
+// Search returns a SearchRepository backed by the store
+func (s *MemoryStore) Search() SearchRepository {
+	return &memorySearchRepository{s}
+}
+
+// sortByName orders genres or tags by name regardless of case, then ID, like byName
+func sortByName[T any](items []T, key func(T) (string, uint)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		nameI, idI := key(items[i])
+		nameJ, idJ := key(items[j])
+		if c := strings.Compare(strings.ToLower(nameI), strings.ToLower(nameJ)); c != 0 {
+			return c < 0
+		}
+		return idI < idJ
+	})
+}
+
+// withDeleted returns the records of live, followed by those of deleted when includeDeleted is set
+func withDeleted[T any](live, deleted map[uint]T, includeDeleted bool) []T {
+	records := slices.Collect(maps.Values(live))
+	if includeDeleted {
+		records = slices.AppendSeq(records, maps.Values(deleted))
+	}
+	return records
+}
+
+// deletedNow returns the DeletedAt of a record deleted now
+func deletedNow() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+// memoryKeyset returns the indexes keysetQuery would select from n rows sorted
+// by the keyset keys, in query order. compare reports how row i relates to the
+// cursor row in that order.
+func memoryKeyset(n int, cursor *Cursor, limit int, compare func(i int) int) []int {
+	var indexes []int
+	if cursor != nil && cursor.Backward {
+		for i := n - 1; i >= 0 && len(indexes) <= limit; i-- {
+			if compare(i) < 0 {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	for i := 0; i < n && len(indexes) <= limit; i++ {
+		if cursor == nil || compare(i) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// compareToCursor compares a row, given by its sort key lookup, to the cursor values
+func compareToCursor(keys []SortField, values []any, key func(field string) any) int {
+	for i, field := range keys {
+		c := compareSortKeys(key(field.Field), values[i])
+		if field.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
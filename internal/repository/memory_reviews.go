@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reviewsByBook returns the stored reviews of a book ordered by ID. Callers must hold the lock.
+func (s *MemoryStore) reviewsByBook(bookID uint) []models.Review {
+	reviews := []models.Review{}
+	for _, review := range s.reviews {
+		if review.BookID == bookID {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID < reviews[j].ID })
+	return reviews
+}
+
+type memoryReviewRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryReviewRepository) Create(review *models.Review) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextReviewID++
+	now := time.Now()
+	review.ID = r.store.nextReviewID
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	review.Version = 1
+
+	stored := *review
+	stored.Book = models.Book{}
+	stored.User = nil
+	r.store.reviews[review.ID] = stored
+	return nil
+}
+
+func (r *memoryReviewRepository) GetByID(id uint) (*models.Review, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	review, ok := r.store.reviews[id]
+	if !ok {
+		return &models.Review{}, errNotFound(resourceReview)
+	}
+	return &review, nil
+}
+
+func (r *memoryReviewRepository) GetByBookID(bookID uint, includeDeleted bool) ([]models.Review, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reviews := r.store.reviewsByBook(bookID)
+	if !includeDeleted {
+		return reviews, nil
+	}
+	for _, review := range r.store.deletedReviews {
+		if review.BookID == bookID {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID < reviews[j].ID })
+	return reviews, nil
+}
+
+func (r *memoryReviewRepository) GetByBookIDCursor(bookID uint, cursor *Cursor, limit int, includeDeleted bool) ([]models.Review, KeysetPage, error) {
+	values, err := idCursorKeys(cursor)
+	if err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceReview)
+	}
+
+	all, _ := r.GetByBookID(bookID, includeDeleted)
+	indexes := memoryKeyset(len(all), cursor, limit, func(i int) int {
+		return compareToCursor(idKeys, values, func(string) any { return all[i].ID })
+	})
+	reviews := make([]models.Review, len(indexes))
+	for i, index := range indexes {
+		reviews[i] = all[index]
+	}
+
+	count, page := keysetPage(len(reviews), limit, nil, cursor, func(i int) []string {
+		return []string{formatSortKey(reviews[i].ID)}
+	})
+	reviews = reviews[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(reviews)
+	}
+	return reviews, page, nil
+}
+
+func (r *memoryReviewRepository) Update(review *models.Review) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.reviews[review.ID]; !ok || current.Version != review.Version {
+		return errModified(resourceReview)
+	}
+
+	review.Version++
+	review.UpdatedAt = time.Now()
+	stored := *review
+	stored.Book = models.Book{}
+	stored.User = nil
+	r.store.reviews[review.ID] = stored
+	return nil
+}
+
+func (r *memoryReviewRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	review, ok := r.store.reviews[id]
+	if !ok {
+		return nil
+	}
+
+	review.DeletedAt = deletedNow()
+	r.store.deletedReviews[id] = review
+	delete(r.store.reviews, id)
+	return nil
+}
+
+func (r *memoryReviewRepository) GetDeletedByID(id uint) (*models.Review, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	review, ok := r.store.deletedReviews[id]
+	if !ok {
+		return &models.Review{}, errNotFound(resourceReview)
+	}
+	return &review, nil
+}
+
+func (r *memoryReviewRepository) Restore(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	review, ok := r.store.deletedReviews[id]
+	if !ok {
+		return errNotFound(resourceReview)
+	}
+
+	review.DeletedAt = gorm.DeletedAt{}
+	review.Version++
+	r.store.reviews[id] = review
+	delete(r.store.deletedReviews, id)
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"sort"
+)
+
+type memorySearchRepository struct {
+	store *MemoryStore
+}
+
+func (r *memorySearchRepository) Search(query, kind string, limit int) ([]SearchHit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []SearchHit{}, nil
+	}
+
+	var hits []SearchHit
+
+	if kind == "" || kind == SearchTypeBook {
+		isbn13 := queryISBN(query)
+		for _, book := range r.store.books {
+			book := book
+			if matchesAll(terms, book.Title, book.Description) || matchedEdition(book, isbn13) != nil {
+				hits = append(hits, scoreBook(&book, terms, isbn13))
+			}
+		}
+	}
+
+	if kind == "" || kind == SearchTypeAuthor {
+		for _, author := range r.store.authors {
+			author := author
+			if matchesAll(terms, author.Name) {
+				hits = append(hits, scoreAuthor(&author, terms))
+			}
+		}
+	}
+
+	// Map iteration order is random, sort by ID first so ties are stable
+	sort.Slice(hits, func(i, j int) bool { return hitID(hits[i]) < hitID(hits[j]) })
+	return sortHits(hits, limit), nil
+}
+
+func hitID(hit SearchHit) uint {
+	if hit.Book != nil {
+		return hit.Book.ID
+	}
+	return hit.Author.ID
+}
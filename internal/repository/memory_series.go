@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// seriesBooks returns the books of a series in reading order, books without a
+// position last. Callers must hold the lock.
+func (s *MemoryStore) seriesBooks(seriesID uint) []models.Book {
+	books := []models.Book{}
+	for _, book := range s.books {
+		if book.SeriesID != nil && *book.SeriesID == seriesID {
+			books = append(books, book)
+		}
+	}
+	sort.Slice(books, func(i, j int) bool {
+		a, b := books[i].SeriesPosition, books[j].SeriesPosition
+		switch {
+		case a == nil || b == nil:
+			if (a == nil) != (b == nil) {
+				return b == nil
+			}
+		case *a != *b:
+			return *a < *b
+		}
+		return books[i].ID < books[j].ID
+	})
+	return books
+}
+
+// seriesNeighbours sets the books before and after a book in its series.
+// Callers must hold the lock.
+func (s *MemoryStore) seriesNeighbours(book *models.Book) {
+	book.PreviousInSeries, book.NextInSeries = nil, nil
+	if book.SeriesID == nil {
+		return
+	}
+
+	books := s.seriesBooks(*book.SeriesID)
+	i := slices.IndexFunc(books, func(other models.Book) bool { return other.ID == book.ID })
+	if i > 0 {
+		book.PreviousInSeries = &books[i-1]
+	}
+	if i >= 0 && i < len(books)-1 {
+		book.NextInSeries = &books[i+1]
+	}
+}
+
+// positionTaken reports whether another live book of the series of a book has
+// its position, mirroring the unique index. Callers must hold the lock.
+func (s *MemoryStore) positionTaken(book models.Book) bool {
+	if book.SeriesID == nil || book.SeriesPosition == nil {
+		return false
+	}
+	for _, other := range s.books {
+		if other.ID != book.ID && other.SeriesID != nil && *other.SeriesID == *book.SeriesID &&
+			other.SeriesPosition != nil && *other.SeriesPosition == *book.SeriesPosition {
+			return true
+		}
+	}
+	return false
+}
+
+type memorySeriesRepository struct {
+	store *MemoryStore
+}
+
+func (r *memorySeriesRepository) Create(series *models.Series) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextSeriesID++
+	now := time.Now()
+	series.ID = r.store.nextSeriesID
+	series.CreatedAt = now
+	series.UpdatedAt = now
+	series.Version = 1
+	r.store.series[series.ID] = *series
+	return nil
+}
+
+func (r *memorySeriesRepository) GetByID(id uint) (*models.Series, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	series, ok := r.store.series[id]
+	if !ok {
+		return &models.Series{}, errNotFound(resourceSeries)
+	}
+	return &series, nil
+}
+
+func (r *memorySeriesRepository) GetAll(page, pageSize int) ([]models.Series, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := slices.Collect(maps.Values(r.store.series))
+	sortByName(all, func(series models.Series) (string, uint) { return series.Name, series.ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memorySeriesRepository) CountBooks(seriesIDs []uint) (map[uint]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64, len(seriesIDs))
+	for _, book := range r.store.books {
+		if book.SeriesID != nil && slices.Contains(seriesIDs, *book.SeriesID) {
+			counts[*book.SeriesID]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memorySeriesRepository) GetBooks(seriesID uint) ([]models.Book, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.seriesBooks(seriesID), nil
+}
+
+func (r *memorySeriesRepository) Update(series *models.Series) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.series[series.ID]; !ok || current.Version != series.Version {
+		return errModified(resourceSeries)
+	}
+
+	series.Version++
+	series.UpdatedAt = time.Now()
+	r.store.series[series.ID] = *series
+	return nil
+}
+
+func (r *memorySeriesRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(r.store.seriesBooks(id)) > 0 {
+		return errInUse(resourceSeries)
+	}
+
+	// Restoring a deleted book does not bring the series back
+	for bookID, book := range r.store.deletedBooks {
+		if book.SeriesID != nil && *book.SeriesID == id {
+			book.SeriesID, book.SeriesPosition = nil, nil
+			r.store.deletedBooks[bookID] = book
+		}
+	}
+	delete(r.store.series, id)
+	return nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortSubjects orders subjects by a key, then ID
+func sortSubjects(subjects []models.Subject, key func(models.Subject) string) {
+	sort.SliceStable(subjects, func(i, j int) bool {
+		if c := strings.Compare(key(subjects[i]), key(subjects[j])); c != 0 {
+			return c < 0
+		}
+		return subjects[i].ID < subjects[j].ID
+	})
+}
+
+type memorySubjectRepository struct {
+	store *MemoryStore
+}
+
+func (r *memorySubjectRepository) Create(subject *models.Subject, parentPath string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.codeTaken(*subject) {
+		return errDuplicate(resourceSubject)
+	}
+
+	r.store.nextSubjectID++
+	now := time.Now()
+	subject.ID = r.store.nextSubjectID
+	subject.CreatedAt = now
+	subject.UpdatedAt = now
+	subject.Version = 1
+	subject.Path = models.SubjectPath(parentPath, subject.ID)
+	r.store.subjects[subject.ID] = *subject
+	return nil
+}
+
+// codeTaken mirrors the unique index on subjects.code. Callers must hold the lock.
+func (r *memorySubjectRepository) codeTaken(subject models.Subject) bool {
+	for id, other := range r.store.subjects {
+		if id != subject.ID && other.Code == subject.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memorySubjectRepository) GetByID(id uint) (*models.Subject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subject, ok := r.store.subjects[id]
+	if !ok {
+		return &models.Subject{}, errNotFound(resourceSubject)
+	}
+	return &subject, nil
+}
+
+func (r *memorySubjectRepository) GetByIDs(ids []uint) ([]models.Subject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subjects := []models.Subject{}
+	for _, id := range ids {
+		if subject, ok := r.store.subjects[id]; ok && !slices.ContainsFunc(subjects, func(s models.Subject) bool { return s.ID == id }) {
+			subjects = append(subjects, subject)
+		}
+	}
+	sortSubjects(subjects, func(subject models.Subject) string { return subject.Code })
+	return subjects, nil
+}
+
+func (r *memorySubjectRepository) GetChildren(parentID *uint, page, pageSize int) ([]models.Subject, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := []models.Subject{}
+	for _, subject := range r.store.subjects {
+		if (parentID == nil && subject.ParentID == nil) || (parentID != nil && subject.ParentID != nil && *subject.ParentID == *parentID) {
+			all = append(all, subject)
+		}
+	}
+	sortSubjects(all, func(subject models.Subject) string { return subject.Code })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memorySubjectRepository) GetAncestors(subject models.Subject) ([]models.Subject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ancestors := []models.Subject{}
+	for _, id := range subject.AncestorIDs() {
+		if ancestor, ok := r.store.subjects[id]; ok {
+			ancestors = append(ancestors, ancestor)
+		}
+	}
+	return ancestors, nil
+}
+
+func (r *memorySubjectRepository) GetSubtree(subject models.Subject) ([]models.Subject, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subtree := []models.Subject{}
+	for _, other := range r.store.subjects {
+		if strings.HasPrefix(other.Path, subject.Path) {
+			subtree = append(subtree, other)
+		}
+	}
+	sortSubjects(subtree, func(subject models.Subject) string { return subject.Path })
+	return subtree, nil
+}
+
+func (r *memorySubjectRepository) Update(subject *models.Subject) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.subjects[subject.ID]; !ok || current.Version != subject.Version {
+		return errModified(resourceSubject)
+	}
+	if r.codeTaken(*subject) {
+		return errDuplicate(resourceSubject)
+	}
+
+	subject.Version++
+	subject.UpdatedAt = time.Now()
+	r.store.subjects[subject.ID] = *subject
+	return nil
+}
+
+func (r *memorySubjectRepository) Move(subject *models.Subject, parent *models.Subject) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	current, ok := r.store.subjects[subject.ID]
+	if !ok || current.Version != subject.Version {
+		return errModified(resourceSubject)
+	}
+
+	// Like the SQL move, use the stored paths rather than the caller's
+	oldPath, parentPath := current.Path, ""
+	if parent != nil {
+		stored, ok := r.store.subjects[parent.ID]
+		if !ok {
+			return errNotFound(resourceSubject)
+		}
+		parentPath = stored.Path
+		if strings.HasPrefix(parentPath, oldPath) {
+			return errSubjectCycle()
+		}
+	}
+
+	subject.ParentID = nil
+	if parent != nil {
+		subject.ParentID = &parent.ID
+	}
+	subject.Path = models.SubjectPath(parentPath, subject.ID)
+	subject.Version++
+	subject.UpdatedAt = time.Now()
+	r.store.subjects[subject.ID] = *subject
+
+	// Descendants keep the rest of their path below the moved subject
+	for id, other := range r.store.subjects {
+		if rest, ok := strings.CutPrefix(other.Path, oldPath); ok && id != subject.ID {
+			other.Path = subject.Path + rest
+			r.store.subjects[id] = other
+		}
+	}
+	return nil
+}
+
+func (r *memorySubjectRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, subject := range r.store.subjects {
+		if subject.ParentID != nil && *subject.ParentID == id {
+			return errInUse(resourceSubject)
+		}
+	}
+
+	unlink := func(book models.Book) models.Book {
+		book.Subjects = slices.DeleteFunc(slices.Clone(book.Subjects), func(s models.Subject) bool { return s.ID == id })
+		return book
+	}
+	for bookID, book := range r.store.books {
+		r.store.books[bookID] = unlink(book)
+	}
+	for bookID, book := range r.store.deletedBooks {
+		r.store.deletedBooks[bookID] = unlink(book)
+	}
+	delete(r.store.subjects, id)
+	return nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryTagRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryTagRepository) Create(tag *models.Tag) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.nameTaken(*tag) {
+		return errDuplicate(resourceTag)
+	}
+
+	r.store.nextTagID++
+	now := time.Now()
+	tag.ID = r.store.nextTagID
+	tag.CreatedAt = now
+	tag.UpdatedAt = now
+	tag.Version = 1
+	r.store.tags[tag.ID] = *tag
+	return nil
+}
+
+// nameTaken mirrors the unique index on LOWER(tags.name). Callers must hold the lock.
+func (r *memoryTagRepository) nameTaken(tag models.Tag) bool {
+	for id, other := range r.store.tags {
+		if id != tag.ID && strings.EqualFold(other.Name, tag.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryTagRepository) GetByID(id uint) (*models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tag, ok := r.store.tags[id]
+	if !ok {
+		return &models.Tag{}, errNotFound(resourceTag)
+	}
+	return &tag, nil
+}
+
+func (r *memoryTagRepository) GetByIDs(ids []uint) ([]models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tags := []models.Tag{}
+	for _, id := range ids {
+		if tag, ok := r.store.tags[id]; ok && !slices.ContainsFunc(tags, func(t models.Tag) bool { return t.ID == id }) {
+			tags = append(tags, tag)
+		}
+	}
+	sortByName(tags, func(tag models.Tag) (string, uint) { return tag.Name, tag.ID })
+	return tags, nil
+}
+
+func (r *memoryTagRepository) GetAll(page, pageSize int) ([]models.Tag, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := slices.Collect(maps.Values(r.store.tags))
+	sortByName(all, func(tag models.Tag) (string, uint) { return tag.Name, tag.ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryTagRepository) CountBooks(tagIDs []uint) (map[uint]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uint]int64, len(tagIDs))
+	for _, book := range r.store.books {
+		for _, tag := range book.Tags {
+			if slices.Contains(tagIDs, tag.ID) {
+				counts[tag.ID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryTagRepository) Update(tag *models.Tag) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the version check of the SQL update
+	if current, ok := r.store.tags[tag.ID]; !ok || current.Version != tag.Version {
+		return errModified(resourceTag)
+	}
+	if r.nameTaken(*tag) {
+		return errDuplicate(resourceTag)
+	}
+
+	tag.Version++
+	tag.UpdatedAt = time.Now()
+	r.store.tags[tag.ID] = *tag
+	return nil
+}
+
+func (r *memoryTagRepository) Delete(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	unlink := func(book models.Book) models.Book {
+		book.Tags = slices.DeleteFunc(slices.Clone(book.Tags), func(t models.Tag) bool { return t.ID == id })
+		return book
+	}
+	for bookID, book := range r.store.books {
+		r.store.books[bookID] = unlink(book)
+	}
+	for bookID, book := range r.store.deletedBooks {
+		r.store.deletedBooks[bookID] = unlink(book)
+	}
+	delete(r.store.tags, id)
+	return nil
+}
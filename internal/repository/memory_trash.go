@@ -0,0 +1,40 @@
+package repository
+
+import "time"
+
+type memoryTrashRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryTrashRepository) Purge(before time.Time) (PurgeCounts, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var counts PurgeCounts
+	for id, review := range r.store.deletedReviews {
+		if review.DeletedAt.Time.Before(before) {
+			delete(r.store.deletedReviews, id)
+			counts.Reviews++
+		}
+	}
+	for id, book := range r.store.deletedBooks {
+		if !book.DeletedAt.Time.Before(before) {
+			continue
+		}
+		for reviewID, review := range r.store.deletedReviews {
+			if review.BookID == id {
+				delete(r.store.deletedReviews, reviewID)
+			}
+		}
+		delete(r.store.deletedBooks, id)
+		counts.Books++
+	}
+	for id, author := range r.store.deletedAuthors {
+		if !author.DeletedAt.Time.Before(before) || r.store.hasBooks(id) {
+			continue
+		}
+		delete(r.store.deletedAuthors, id)
+		counts.Authors++
+	}
+	return counts, nil
+}
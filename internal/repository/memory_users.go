@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"sort"
+	"time"
+)
+
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryUserRepository) Create(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Mirror the unique index on users.email
+	for _, existing := range r.store.users {
+		if existing.Email == user.Email {
+			return errDuplicate(resourceUser)
+		}
+	}
+
+	r.store.nextUserID++
+	now := time.Now()
+	user.ID = r.store.nextUserID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(id uint) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return &models.User{}, errNotFound(resourceUser)
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) GetByEmail(email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return &models.User{}, errNotFound(resourceUser)
+}
+
+func (r *memoryUserRepository) GetAll(page, pageSize int) ([]models.User, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all := make([]models.User, 0, len(r.store.users))
+	for _, user := range r.store.users {
+		all = append(all, user)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	count := int64(len(all))
+	offset := (page - 1) * pageSize
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := offset + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[offset:end], count, nil
+}
+
+func (r *memoryUserRepository) Update(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+	r.store.users[user.ID] = *user
+	return nil
+}
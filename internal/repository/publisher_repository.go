@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type publisherRepository struct {
+	db *gorm.DB
+}
+
+// NewPublisherRepository returns a GORM-backed PublisherRepository
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &publisherRepository{db: db}
+}
+
+func (r *publisherRepository) Create(publisher *models.Publisher) error {
+	return translateError(r.db.Create(publisher).Error, resourcePublisher)
+}
+
+func (r *publisherRepository) GetByID(id uint) (*models.Publisher, error) {
+	var publisher models.Publisher
+	result := r.db.First(&publisher, id)
+	return &publisher, translateError(result.Error, resourcePublisher)
+}
+
+func (r *publisherRepository) GetAll(page, pageSize int) ([]models.Publisher, int64, error) {
+	var publishers []models.Publisher
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.Publisher{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourcePublisher)
+	}
+
+	// Get paginated publishers
+	offset := (page - 1) * pageSize
+	result := r.db.Order("LOWER(name), id").Offset(offset).Limit(pageSize).Find(&publishers)
+	return publishers, count, translateError(result.Error, resourcePublisher)
+}
+
+func (r *publisherRepository) CountEditions(publisherIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		PublisherID  uint
+		EditionCount int64
+	}
+	counts := make(map[uint]int64, len(publisherIDs))
+	if len(publisherIDs) == 0 {
+		return counts, nil
+	}
+
+	// Editions of deleted books are deleted with them
+	err := r.db.Model(&models.Edition{}).
+		Select("publisher_id, COUNT(*) AS edition_count").
+		Where("publisher_id IN ?", publisherIDs).
+		Group("publisher_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err, resourcePublisher)
+	}
+
+	for _, row := range rows {
+		counts[row.PublisherID] = row.EditionCount
+	}
+	return counts, nil
+}
+
+func (r *publisherRepository) GetEditions(publisherID uint) ([]models.Edition, error) {
+	var editions []models.Edition
+	result := r.db.Where("publisher_id = ?", publisherID).Order("id").Find(&editions)
+	return editions, translateError(result.Error, resourcePublisher)
+}
+
+func (r *publisherRepository) Update(publisher *models.Publisher) error {
+	return updateVersioned(r.db, publisher, &publisher.Version, resourcePublisher)
+}
+
+func (r *publisherRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var editions int64
+		if err := tx.Model(&models.Edition{}).Where("publisher_id = ?", id).Count(&editions).Error; err != nil {
+			return err
+		}
+		if editions > 0 {
+			return errInUse(resourcePublisher)
+		}
+
+		// Restoring a deleted book does not bring the publisher back
+		if err := tx.Unscoped().Model(&models.Edition{}).Where("publisher_id = ?", id).
+			UpdateColumn("publisher_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Publisher{}, id).Error
+	})
+	return translateError(err, resourcePublisher)
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"cmp"
+	"fmt"
+	"go-rest-api/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortField is a single sort key of a listing
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// BookSortFields maps the sortable book fields of the API to their columns
+var BookSortFields = map[string]string{
+	"id":               "id",
+	"title":            "title",
+	"publication_year": "publication_year",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+}
+
+// BookFilter narrows and orders the books listing. Nil pointers, empty
+// strings and empty slices leave the corresponding filter out.
+type BookFilter struct {
+	AuthorID  *uint
+	YearGte   *int
+	YearLte   *int
+	Title     string
+	MinRating *float64
+	// GenreIDs and TagIDs select books in any of the genres or with any of
+	// the tags, or in all of them when the matching All flag is set
+	GenreIDs  []uint
+	AllGenres bool
+	TagIDs    []uint
+	AllTags   bool
+	// SubjectPath selects books filed under the subject with this path or any of its descendants
+	SubjectPath    string
+	Sort           []SortField
+	IncludeDeleted bool
+}
+
+// AuthorFilter selects what the authors listing returns
+type AuthorFilter struct {
+	IncludeBooks   bool
+	IncludeDeleted bool
+}
+
+// ParseSort parses a comma separated sort parameter such as "-publication_year,title".
+// A leading "-" sorts descending. Fields must be keys of allowed.
+func ParseSort(value string, allowed map[string]string) ([]SortField, error) {
+	var fields []SortField
+	if strings.TrimSpace(value) == "" {
+		return fields, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := allowed[field.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", field.Field)
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// orderClause builds an ORDER BY clause from whitelisted sort fields.
+// The id column is always appended so pages are stable.
+func orderClause(table string, fields []SortField, allowed map[string]string) string {
+	parts := make([]string, 0, len(fields)+1)
+	hasID := false
+	for _, field := range fields {
+		column := allowed[field.Field]
+		if column == "id" {
+			hasID = true
+		}
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, fmt.Sprintf("%s.%s %s", table, column, direction))
+	}
+	if !hasID {
+		parts = append(parts, table+".id ASC")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// bookSortKey returns the value of one of the BookSortFields of a book
+func bookSortKey(book models.Book, field string) any {
+	switch field {
+	case "title":
+		return book.Title
+	case "publication_year":
+		return book.PublicationYear
+	case "created_at":
+		return book.CreatedAt
+	case "updated_at":
+		return book.UpdatedAt
+	}
+	return book.ID
+}
+
+// compareSortKeys compares two sort key values of the same type
+func compareSortKeys(a, b any) int {
+	switch a := a.(type) {
+	case uint:
+		return cmp.Compare(a, b.(uint))
+	case int:
+		return cmp.Compare(a, b.(int))
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+// formatSortKey encodes a sort key value for a cursor
+func formatSortKey(value any) string {
+	switch v := value.(type) {
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+// parseSortKey decodes a cursor value into the type of like
+func parseSortKey(like any, value string) (any, error) {
+	switch like.(type) {
+	case uint:
+		v, err := strconv.ParseUint(value, 10, 32)
+		return uint(v), err
+	case int:
+		return strconv.Atoi(value)
+	case time.Time:
+		// Stored timestamps carry the local zone, SQLite compares them as text
+		t, err := time.Parse(time.RFC3339Nano, value)
+		return t.Local(), err
+	}
+	return value, nil
+}
+
+// cursorKeys decodes the values of a cursor into sort keys shaped like the keys of sample
+func cursorKeys(cursor *Cursor, keys []SortField, sample func(field string) any) ([]any, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		value, err := parseSortKey(sample(key.Field), cursor.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied value, for patterns
+// used with ESCAPE '\'
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
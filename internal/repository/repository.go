@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"time"
+)
+
+// BookRepository defines the storage operations for books
+type BookRepository interface {
+	// Create saves a book together with its first edition, which has the book's ISBN
+	Create(book *models.Book) error
+	// GetByID, GetByISBN and GetByISBNs load books with their details, which
+	// include their editions and the books before and after them in their series.
+	// Like GetAll and GetAllByCursor, they also count the copies of the books.
+	GetByID(id uint) (*models.Book, error)
+	// GetByISBN and GetByISBNs look books up by the normalized ISBN-13 of any of their editions
+	GetByISBN(isbn13 string) (*models.Book, error)
+	GetByISBNs(isbn13s []string) ([]models.Book, error)
+	GetAll(filter BookFilter, page, pageSize int) ([]models.Book, int64, error)
+	GetAllByCursor(filter BookFilter, cursor *Cursor, limit int) ([]models.Book, KeysetPage, error)
+	// Update saves a book, gives its first edition the book's ISBN and reloads
+	// the books before and after it in its series and its copy counts
+	Update(book *models.Book) error
+	// Delete soft deletes a book together with its reviews, editions and copies
+	Delete(id uint) error
+	// GetDeletedByID returns a soft deleted book, not found if it is not deleted
+	GetDeletedByID(id uint) (*models.Book, error)
+	// Restore undeletes a book and the reviews, editions and copies that were
+	// deleted with it. It fails with book_exists when another book now has one
+	// of its ISBNs and copy_exists when a copy now has one of its barcodes.
+	Restore(id uint) error
+}
+
+// AuthorRepository defines the storage operations for authors
+type AuthorRepository interface {
+	Create(author *models.Author) error
+	GetByID(id uint) (*models.Author, error)
+	GetAll(filter AuthorFilter, page, pageSize int) ([]models.Author, int64, error)
+	GetAllByCursor(filter AuthorFilter, cursor *Cursor, limit int) ([]models.Author, KeysetPage, error)
+	CountBooks(authorIDs []uint) (map[uint]int64, error)
+	Update(author *models.Author) error
+	// Delete soft deletes an author, which fails with author_in_use while it
+	// has books unless deletion says what to do with them
+	Delete(id uint, deletion AuthorDeletion) error
+	GetDeletedByID(id uint) (*models.Author, error)
+	Restore(id uint) error
+}
+
+// AuthorDeletion says what happens to the books of an author being deleted
+type AuthorDeletion struct {
+	// Cascade soft deletes the books and their reviews with the author
+	Cascade bool
+	// ReassignTo moves the books to another author before the author is deleted
+	ReassignTo *uint
+}
+
+// GenreRepository defines the storage operations for genres
+type GenreRepository interface {
+	Create(genre *models.Genre) error
+	GetByID(id uint) (*models.Genre, error)
+	// GetByIDs returns the genres with the given IDs ordered by name, leaving out those that do not exist
+	GetByIDs(ids []uint) ([]models.Genre, error)
+	GetAll(page, pageSize int) ([]models.Genre, int64, error)
+	CountBooks(genreIDs []uint) (map[uint]int64, error)
+	Update(genre *models.Genre) error
+	// Delete removes a genre and takes it off its books
+	Delete(id uint) error
+}
+
+// TagRepository defines the storage operations for tags
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	GetByID(id uint) (*models.Tag, error)
+	// GetByIDs returns the tags with the given IDs ordered by name, leaving out those that do not exist
+	GetByIDs(ids []uint) ([]models.Tag, error)
+	GetAll(page, pageSize int) ([]models.Tag, int64, error)
+	CountBooks(tagIDs []uint) (map[uint]int64, error)
+	Update(tag *models.Tag) error
+	// Delete removes a tag and takes it off its books
+	Delete(id uint) error
+}
+
+// SubjectRepository defines the storage operations for the subject tree
+type SubjectRepository interface {
+	// Create adds a subject under the subject with parentPath, "" for a top level subject
+	Create(subject *models.Subject, parentPath string) error
+	GetByID(id uint) (*models.Subject, error)
+	// GetByIDs returns the subjects with the given IDs ordered by code, leaving out those that do not exist
+	GetByIDs(ids []uint) ([]models.Subject, error)
+	// GetChildren lists the children of a subject, or the top level subjects for nil, ordered by code
+	GetChildren(parentID *uint, page, pageSize int) ([]models.Subject, int64, error)
+	// GetAncestors returns the ancestors of a subject from the top level down
+	GetAncestors(subject models.Subject) ([]models.Subject, error)
+	// GetSubtree returns a subject and all of its descendants ordered by path
+	GetSubtree(subject models.Subject) ([]models.Subject, error)
+	Update(subject *models.Subject) error
+	// Move makes a subject, with its subtree, a child of parent or a top level
+	// subject for nil. It fails with subject_cycle when parent is in the
+	// subtree, checked against the paths stored when the move is made.
+	Move(subject *models.Subject, parent *models.Subject) error
+	// Delete removes a subject and takes it off its books, which fails with
+	// subject_in_use while it has children
+	Delete(id uint) error
+}
+
+// SeriesRepository defines the storage operations for series
+type SeriesRepository interface {
+	Create(series *models.Series) error
+	GetByID(id uint) (*models.Series, error)
+	GetAll(page, pageSize int) ([]models.Series, int64, error)
+	CountBooks(seriesIDs []uint) (map[uint]int64, error)
+	// GetBooks returns the books of a series in reading order, by position then
+	// ID, books without a position last
+	GetBooks(seriesID uint) ([]models.Book, error)
+	Update(series *models.Series) error
+	// Delete removes a series, which fails with series_in_use while it has
+	// books. Deleted books in the series are taken out of it.
+	Delete(id uint) error
+}
+
+// EditionRepository defines the storage operations for the editions of books
+type EditionRepository interface {
+	Create(edition *models.Edition) error
+	// GetByID and GetByBookID load editions with their publisher
+	GetByID(id uint) (*models.Edition, error)
+	// GetByBookID returns the editions of a book in the order they were added
+	GetByBookID(bookID uint) ([]models.Edition, error)
+	// Update saves an edition. Changing the ISBN of a book's first edition
+	// changes the ISBN of the book.
+	Update(edition *models.Edition) error
+	// Delete removes an edition, which fails with edition_in_use for the first
+	// edition of a book
+	Delete(id uint) error
+}
+
+// PublisherRepository defines the storage operations for publishers
+type PublisherRepository interface {
+	Create(publisher *models.Publisher) error
+	GetByID(id uint) (*models.Publisher, error)
+	GetAll(page, pageSize int) ([]models.Publisher, int64, error)
+	CountEditions(publisherIDs []uint) (map[uint]int64, error)
+	// GetEditions returns the editions of a publisher ordered by ID
+	GetEditions(publisherID uint) ([]models.Edition, error)
+	Update(publisher *models.Publisher) error
+	// Delete removes a publisher, which fails with publisher_in_use while it
+	// has editions. Editions of deleted books lose their publisher.
+	Delete(id uint) error
+}
+
+// CopyRepository defines the storage operations for the physical copies of books
+type CopyRepository interface {
+	Create(bookCopy *models.Copy) error
+	GetByID(id uint) (*models.Copy, error)
+	// GetByBookID returns the copies of a book ordered by ID, only those with
+	// the given status unless it is empty
+	GetByBookID(bookID uint, status string) ([]models.Copy, error)
+	Update(bookCopy *models.Copy) error
+	// Delete removes a copy for good, copies are only soft deleted with their book
+	Delete(id uint) error
+}
+
+// ReviewRepository defines the storage operations for reviews
+type ReviewRepository interface {
+	Create(review *models.Review) error
+	GetByID(id uint) (*models.Review, error)
+	GetByBookID(bookID uint, includeDeleted bool) ([]models.Review, error)
+	GetByBookIDCursor(bookID uint, cursor *Cursor, limit int, includeDeleted bool) ([]models.Review, KeysetPage, error)
+	Update(review *models.Review) error
+	Delete(id uint) error
+	GetDeletedByID(id uint) (*models.Review, error)
+	Restore(id uint) error
+}
+
+// PurgeCounts is the number of records of each resource that a purge removed
+type PurgeCounts struct {
+	Books   int64
+	Reviews int64
+	Authors int64
+}
+
+// TrashRepository permanently removes soft deleted records
+type TrashRepository interface {
+	// Purge removes the reviews, books, with their editions and copies, and
+	// authors deleted before the given time. Authors that a book still refers
+	// to are kept. It runs in one transaction, so on error nothing is removed.
+	Purge(before time.Time) (PurgeCounts, error)
+}
+
+// Search result types
+const (
+	SearchTypeBook   = "book"
+	SearchTypeAuthor = "author"
+)
+
+// SearchHit is a single ranked search result, either a book or an author
+type SearchHit struct {
+	Type       string
+	Book       *models.Book
+	Author     *models.Author
+	Rank       float64
+	Highlights map[string]string
+}
+
+// SearchRepository defines full-text search across books and authors
+type SearchRepository interface {
+	// Search returns up to limit hits ordered by relevance. kind restricts
+	// the results to SearchTypeBook or SearchTypeAuthor, "" returns both.
+	Search(query, kind string, limit int) ([]SearchHit, error)
+}
+
+// UserRepository defines the storage operations for user accounts
+type UserRepository interface {
+	Create(user *models.User) error
+	GetByID(id uint) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	GetAll(page, pageSize int) ([]models.User, int64, error)
+	Update(user *models.User) error
+}
+
+// APIKeyRepository defines the storage operations for API keys
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id uint) (*models.APIKey, error)
+	GetByHash(hash string) (*models.APIKey, error)
+	GetAll(page, pageSize int) ([]models.APIKey, int64, error)
+	Update(key *models.APIKey) error
+	// TouchLastUsed records when a key was last used without bumping updated_at
+	TouchLastUsed(id uint, at time.Time) error
+	Delete(id uint) error
+}
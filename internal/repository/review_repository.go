@@ -1,30 +1,87 @@
 package repository
 
 import (
-	"go-rest-api/internal/database"
 	"go-rest-api/internal/models"
+	"slices"
+
+	"gorm.io/gorm"
 )
 
-func CreateReview(review *models.Review) error {
-	return database.DB.Create(review).Error
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository returns a GORM-backed ReviewRepository
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(review *models.Review) error {
+	return translateError(r.db.Create(review).Error, resourceReview)
 }
 
-func GetReviewsByBookID(bookID uint) ([]models.Review, error) {
+func (r *reviewRepository) GetByBookID(bookID uint, includeDeleted bool) ([]models.Review, error) {
 	var reviews []models.Review
-	result := database.DB.Where("book_id = ?", bookID).Find(&reviews)
-	return reviews, result.Error
+	result := r.byBook(bookID, includeDeleted).Find(&reviews)
+	return reviews, translateError(result.Error, resourceReview)
 }
 
-func GetReviewByID(id uint) (*models.Review, error) {
+func (r *reviewRepository) GetByBookIDCursor(bookID uint, cursor *Cursor, limit int, includeDeleted bool) ([]models.Review, KeysetPage, error) {
+	values, err := idCursorKeys(cursor)
+	if err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceReview)
+	}
+
+	var reviews []models.Review
+	query := keysetQuery(r.byBook(bookID, includeDeleted), "reviews", idKeys, idColumns, values, cursor, limit)
+	if err := query.Find(&reviews).Error; err != nil {
+		return nil, KeysetPage{}, translateError(err, resourceReview)
+	}
+
+	count, page := keysetPage(len(reviews), limit, nil, cursor, func(i int) []string {
+		return []string{formatSortKey(reviews[i].ID)}
+	})
+	reviews = reviews[:count]
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(reviews)
+	}
+	return reviews, page, nil
+}
+
+// byBook selects the reviews of a book
+func (r *reviewRepository) byBook(bookID uint, includeDeleted bool) *gorm.DB {
+	query := r.db.Where("book_id = ?", bookID)
+	if includeDeleted {
+		query = query.Unscoped()
+	}
+	return query
+}
+
+func (r *reviewRepository) GetByID(id uint) (*models.Review, error) {
 	var review models.Review
-	result := database.DB.First(&review, id)
-	return &review, result.Error
+	result := r.db.First(&review, id)
+	return &review, translateError(result.Error, resourceReview)
+}
+
+func (r *reviewRepository) Update(review *models.Review) error {
+	return updateVersioned(r.db, review, &review.Version, resourceReview)
+}
+
+func (r *reviewRepository) Delete(id uint) error {
+	return translateError(r.db.Delete(&models.Review{}, id).Error, resourceReview)
 }
 
-func UpdateReview(review *models.Review) error {
-	return database.DB.Save(review).Error
+func (r *reviewRepository) GetDeletedByID(id uint) (*models.Review, error) {
+	var review models.Review
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&review, id)
+	return &review, translateError(result.Error, resourceReview)
 }
 
-func DeleteReview(id uint) error {
-	return database.DB.Delete(&models.Review{}, id).Error
+func (r *reviewRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Review{}).Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumns(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return errNotFound(resourceReview)
+	}
+	return translateError(result.Error, resourceReview)
 }
@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/isbn"
+	"go-rest-api/internal/models"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Markers placed around matched terms in highlights. The rest of a highlight
+// is HTML escaped, so that clients can render highlights as they are.
+const (
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+)
+
+// ts_headline marks matches with these private use characters, which replace
+// the markers once the headline is escaped. They are removed from the text
+// before it is passed to ts_headline, so they only ever come from it.
+const (
+	headlineStart = "\uE000"
+	headlineStop  = "\uE001"
+)
+
+// headlineOptions returns ts_headline options that mark matches with the sentinels
+func headlineOptions(extra string) string {
+	return "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", " + extra
+}
+
+// NewSearchRepository returns a SearchRepository for db. Postgres uses the
+// tsvector columns from migration 0002, other dialects fall back to LIKE.
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	if db.Dialector.Name() == "postgres" {
+		return &postgresSearchRepository{db: db}
+	}
+	return &likeSearchRepository{db: db}
+}
+
+type postgresSearchRepository struct {
+	db *gorm.DB
+}
+
+type postgresBookHit struct {
+	models.Book
+	Rank                 float64
+	MatchedIsbn          *string
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+type postgresAuthorHit struct {
+	models.Author
+	Rank          float64
+	NameHighlight string
+}
+
+func (r *postgresSearchRepository) Search(query, kind string, limit int) ([]SearchHit, error) {
+	var hits []SearchHit
+
+	if kind == "" || kind == SearchTypeBook {
+		// A query that is an ISBN also finds the book with an edition of that
+		// ISBN in any form, by its normalized value
+		var rows []postgresBookHit
+		err := r.db.Raw(`
+			SELECT books.*,
+				ts_rank(books.search_vector, query) AS rank,
+				matched.isbn AS matched_isbn,
+				ts_headline('english', translate(coalesce(books.title, ''), @sentinels, ''), query,
+					@title_options) AS title_highlight,
+				ts_headline('english', translate(coalesce(books.description, ''), @sentinels, ''), query,
+					@description_options) AS description_highlight
+			FROM books
+				CROSS JOIN websearch_to_tsquery('english', @query) AS query
+				LEFT JOIN editions AS matched ON matched.book_id = books.id
+					AND matched.deleted_at IS NULL AND matched.isbn13 = @isbn13
+			WHERE books.deleted_at IS NULL
+				AND (books.search_vector @@ query OR matched.id IS NOT NULL)
+			ORDER BY matched.id IS NOT NULL DESC, rank DESC
+			LIMIT @limit`, map[string]any{
+			"query":               query,
+			"isbn13":              queryISBN(query),
+			"sentinels":           headlineStart + headlineStop,
+			"title_options":       headlineOptions("HighlightAll=true"),
+			"description_options": headlineOptions("MaxFragments=2"),
+			"limit":               limit,
+		}).Scan(&rows).Error
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+
+		for i := range rows {
+			row := rows[i]
+			highlights := make(map[string]string)
+			addHighlight(highlights, "title", row.TitleHighlight)
+			addHighlight(highlights, "description", row.DescriptionHighlight)
+			if row.MatchedIsbn != nil {
+				row.Rank++
+				highlights["isbn"] = markAll(*row.MatchedIsbn)
+			}
+			hits = append(hits, SearchHit{Type: SearchTypeBook, Book: &row.Book, Rank: row.Rank, Highlights: highlights})
+		}
+	}
+
+	if kind == "" || kind == SearchTypeAuthor {
+		var rows []postgresAuthorHit
+		err := r.db.Raw(`
+			SELECT authors.*,
+				ts_rank(authors.search_vector, query) AS rank,
+				ts_headline('simple', translate(coalesce(authors.name, ''), @sentinels, ''), query,
+					@name_options) AS name_highlight
+			FROM authors, websearch_to_tsquery('simple', @query) AS query
+			WHERE authors.deleted_at IS NULL AND authors.search_vector @@ query
+			ORDER BY rank DESC
+			LIMIT @limit`, map[string]any{
+			"query":        query,
+			"sentinels":    headlineStart + headlineStop,
+			"name_options": headlineOptions("HighlightAll=true"),
+			"limit":        limit,
+		}).Scan(&rows).Error
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+
+		for i := range rows {
+			row := rows[i]
+			highlights := make(map[string]string)
+			addHighlight(highlights, "name", row.NameHighlight)
+			hits = append(hits, SearchHit{Type: SearchTypeAuthor, Author: &row.Author, Rank: row.Rank, Highlights: highlights})
+		}
+	}
+
+	return sortHits(hits, limit), nil
+}
+
+// addHighlight keeps a ts_headline result only if it marked a match, escaping
+// it and replacing the sentinels with the markers
+func addHighlight(highlights map[string]string, field, value string) {
+	if strings.Contains(value, headlineStart) {
+		highlights[field] = strings.NewReplacer(headlineStart, highlightStart, headlineStop, highlightStop).
+			Replace(html.EscapeString(value))
+	}
+}
+
+// markAll escapes text and wraps all of it with highlight markers
+func markAll(text string) string {
+	return highlightStart + html.EscapeString(text) + highlightStop
+}
+
+// queryISBN returns the normalized ISBN-13 of a query that is an ISBN-10 or
+// ISBN-13 in any hyphenation, and nil for other queries
+func queryISBN(query string) *string {
+	isbn13, err := isbn.Normalize(query)
+	if err != nil {
+		return nil
+	}
+	return &isbn13
+}
+
+type likeSearchRepository struct {
+	db *gorm.DB
+}
+
+func (r *likeSearchRepository) Search(query, kind string, limit int) ([]SearchHit, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []SearchHit{}, nil
+	}
+
+	var hits []SearchHit
+
+	if kind == "" || kind == SearchTypeBook {
+		// Every term has to match the title or description, unless the query
+		// is the ISBN of one of the book's editions
+		matches := r.db
+		var rank []clause.Expr
+		for _, term := range terms {
+			pattern := "%" + escapeLike(term) + "%"
+			matches = matches.Where(`LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\'`, pattern, pattern)
+			rank = append(rank, occurrences("title", term), gorm.Expr("0.4 * ?", occurrences("description", term)))
+		}
+		tx := r.db.Model(&models.Book{})
+		isbn13 := queryISBN(query)
+		if isbn13 != nil {
+			matches = matches.Or("id IN (?)", booksWithISBNs(r.db, []string{*isbn13}))
+			rank = append(rank, gorm.Expr("CASE WHEN id IN (?) THEN 1 ELSE 0 END", booksWithISBNs(r.db, []string{*isbn13})))
+			tx = tx.Preload("Editions", "isbn13 = ?", *isbn13)
+		}
+
+		var books []models.Book
+		if err := tx.Where(matches).Clauses(bestFirst(rank)).Limit(limit).Find(&books).Error; err != nil {
+			return nil, apperr.Internal(err)
+		}
+		for i := range books {
+			hits = append(hits, scoreBook(&books[i], terms, isbn13))
+		}
+	}
+
+	if kind == "" || kind == SearchTypeAuthor {
+		tx := r.db.Model(&models.Author{})
+		var rank []clause.Expr
+		for _, term := range terms {
+			tx = tx.Where(`LOWER(name) LIKE ? ESCAPE '\'`, "%"+escapeLike(term)+"%")
+			rank = append(rank, occurrences("name", term))
+		}
+
+		var authors []models.Author
+		if err := tx.Clauses(bestFirst(rank)).Limit(limit).Find(&authors).Error; err != nil {
+			return nil, apperr.Internal(err)
+		}
+		for i := range authors {
+			hits = append(hits, scoreAuthor(&authors[i], terms))
+		}
+	}
+
+	return sortHits(hits, limit), nil
+}
+
+// occurrences counts the occurrences of a lower-cased term in a column in SQL,
+// like countMatches does in Go
+func occurrences(column, term string) clause.Expr {
+	lower := "LOWER(COALESCE(" + column + ", ''))"
+	return gorm.Expr("(LENGTH("+lower+") - LENGTH(REPLACE("+lower+", ?, ''))) / ?", term, utf8.RuneCountInString(term))
+}
+
+// bestFirst orders rows by the sum of rank, highest first, then by ID. The
+// LIKE fallback only loads as many rows as it returns, so rank must add up to
+// the score the rows get in Go.
+func bestFirst(rank []clause.Expr) clause.OrderBy {
+	sum := make([]string, len(rank))
+	vars := make([]any, len(rank))
+	for i, expr := range rank {
+		sum[i], vars[i] = "?", expr
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: "(" + strings.Join(sum, " + ") + ") DESC, id", Vars: vars}}
+}
+
+// searchTerms splits a query into lower-cased terms
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// scoreBook ranks a book found by the LIKE fallback. Title and ISBN matches
+// weigh more than description matches, mirroring the tsvector weights. The
+// ISBN matches when the book has an edition with isbn13, which is nil when
+// the query is not an ISBN.
+func scoreBook(book *models.Book, terms []string, isbn13 *string) SearchHit {
+	hit := SearchHit{Type: SearchTypeBook, Book: book, Highlights: make(map[string]string)}
+
+	if n := countMatches(book.Title, terms); n > 0 {
+		hit.Rank += float64(n)
+		hit.Highlights["title"] = highlight(book.Title, terms)
+	}
+	if n := countMatches(book.Description, terms); n > 0 {
+		hit.Rank += 0.4 * float64(n)
+		hit.Highlights["description"] = highlight(book.Description, terms)
+	}
+	if edition := matchedEdition(*book, isbn13); edition != nil {
+		hit.Rank++
+		hit.Highlights["isbn"] = markAll(edition.ISBN)
+	}
+	return hit
+}
+
+// matchedEdition returns the loaded edition of a book with the normalized ISBN, if any
+func matchedEdition(book models.Book, isbn13 *string) *models.Edition {
+	if isbn13 == nil {
+		return nil
+	}
+	for i, edition := range book.Editions {
+		if edition.ISBN13 != nil && *edition.ISBN13 == *isbn13 {
+			return &book.Editions[i]
+		}
+	}
+	return nil
+}
+
+// scoreAuthor ranks an author found by the LIKE fallback
+func scoreAuthor(author *models.Author, terms []string) SearchHit {
+	hit := SearchHit{Type: SearchTypeAuthor, Author: author, Highlights: make(map[string]string)}
+	if n := countMatches(author.Name, terms); n > 0 {
+		hit.Rank = float64(n)
+		hit.Highlights["name"] = highlight(author.Name, terms)
+	}
+	return hit
+}
+
+// matchesAll reports whether every term occurs in at least one of the fields
+func matchesAll(terms []string, fields ...string) bool {
+	for _, term := range terms {
+		found := false
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// countMatches counts the occurrences of all terms in text
+func countMatches(text string, terms []string) int {
+	lower := strings.ToLower(text)
+	count := 0
+	for _, term := range terms {
+		count += strings.Count(lower, term)
+	}
+	return count
+}
+
+// maxHighlightLength bounds the snippet returned for long fields such as descriptions
+const maxHighlightLength = 200
+
+// highlight wraps every occurrence of the terms in text with highlight markers,
+// escaping the text around and between them. Long texts are cut to a window
+// around the first match.
+func highlight(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lower-casing changed byte offsets, fall back to the original text
+		lower = text
+	}
+
+	// Mark the byte ranges covered by any term
+	marked := make([]bool, len(text))
+	first := -1
+	for _, term := range terms {
+		for start := 0; ; {
+			i := strings.Index(lower[start:], term)
+			if i < 0 {
+				break
+			}
+			i += start
+			for j := i; j < i+len(term); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+			start = i + len(term)
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	from, to := 0, len(text)
+	if len(text) > maxHighlightLength {
+		from = first - maxHighlightLength/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + maxHighlightLength
+		if to > len(text) {
+			to = len(text)
+		}
+		for from > 0 && !utf8.RuneStart(text[from]) {
+			from--
+		}
+		for to < len(text) && !utf8.RuneStart(text[to]) {
+			to++
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	// Escape runs of marked and unmarked bytes, which end on rune boundaries
+	for i := from; i < to; {
+		j := i
+		for j < to && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString(markAll(text[i:j]))
+		} else {
+			b.WriteString(html.EscapeString(text[i:j]))
+		}
+		i = j
+	}
+	if to < len(text) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
+
+// sortHits orders hits by rank, books before authors on ties, and applies the limit
+func sortHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Type == SearchTypeBook && hits[j].Type == SearchTypeAuthor
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	if hits == nil {
+		hits = []SearchHit{}
+	}
+	return hits
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+	"go-rest-api/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestSearchLimit(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		author := createAuthor(t, b, "Dune Fan")
+		for i, book := range []models.Book{
+			{Title: "Dune", Description: "a novel"},                     // ranks 1
+			{Title: "Dune and dune", Description: "a novel"},            // ranks 2
+			{Title: "Other", Description: "set on dune"},                // ranks 0.4
+			{Title: "Dune Messiah", Description: "dune, dune and dune"}, // ranks 2.2
+			{Title: "Other", Description: "a novel"},
+		} {
+			book.AuthorID, book.ISBN = author.ID, fmt.Sprint(i)
+			if err := b.books.Create(&book); err != nil {
+				t.Fatalf("create book: %v", err)
+			}
+		}
+
+		tests := []struct {
+			kind  string
+			limit int
+			want  []string
+		}{
+			{kind: SearchTypeBook, limit: 2, want: []string{"book 4", "book 2"}},
+			{kind: SearchTypeBook, limit: 10, want: []string{"book 4", "book 2", "book 1", "book 3"}},
+			{kind: "", limit: 3, want: []string{"book 4", "book 2", "book 1"}},
+			{kind: "", limit: 4, want: []string{"book 4", "book 2", "book 1", "author 1"}},
+			{kind: SearchTypeAuthor, limit: 1, want: []string{"author 1"}},
+		}
+		for _, tt := range tests {
+			hits, err := b.search.Search("dune", tt.kind, tt.limit)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			got := make([]string, len(hits))
+			for i, hit := range hits {
+				got[i] = fmt.Sprintf("%s %d", hit.Type, hitID(hit))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q, %d) = %v, want %v", tt.kind, tt.limit, got, tt.want)
+			}
+		}
+	})
+}
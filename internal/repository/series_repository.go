@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepository returns a GORM-backed SeriesRepository
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+func (r *seriesRepository) Create(series *models.Series) error {
+	return translateError(r.db.Create(series).Error, resourceSeries)
+}
+
+func (r *seriesRepository) GetByID(id uint) (*models.Series, error) {
+	var series models.Series
+	result := r.db.First(&series, id)
+	return &series, translateError(result.Error, resourceSeries)
+}
+
+func (r *seriesRepository) GetAll(page, pageSize int) ([]models.Series, int64, error) {
+	var series []models.Series
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.Series{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceSeries)
+	}
+
+	// Get paginated series
+	offset := (page - 1) * pageSize
+	result := r.db.Order("LOWER(name), id").Offset(offset).Limit(pageSize).Find(&series)
+	return series, count, translateError(result.Error, resourceSeries)
+}
+
+func (r *seriesRepository) CountBooks(seriesIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		SeriesID  uint
+		BookCount int64
+	}
+	counts := make(map[uint]int64, len(seriesIDs))
+	if len(seriesIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.Model(&models.Book{}).
+		Select("series_id, COUNT(*) AS book_count").
+		Where("series_id IN ?", seriesIDs).
+		Group("series_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err, resourceSeries)
+	}
+
+	for _, row := range rows {
+		counts[row.SeriesID] = row.BookCount
+	}
+	return counts, nil
+}
+
+func (r *seriesRepository) GetBooks(seriesID uint) ([]models.Book, error) {
+	var books []models.Book
+	result := r.db.Where("series_id = ?", seriesID).Order("series_position IS NULL, series_position, id").Find(&books)
+	return books, translateError(result.Error, resourceSeries)
+}
+
+func (r *seriesRepository) Update(series *models.Series) error {
+	return updateVersioned(r.db, series, &series.Version, resourceSeries)
+}
+
+func (r *seriesRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var books int64
+		if err := tx.Model(&models.Book{}).Where("series_id = ?", id).Count(&books).Error; err != nil {
+			return err
+		}
+		if books > 0 {
+			return errInUse(resourceSeries)
+		}
+
+		// Restoring a deleted book does not bring the series back
+		if err := tx.Unscoped().Model(&models.Book{}).Where("series_id = ?", id).
+			UpdateColumns(map[string]any{"series_id": nil, "series_position": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Series{}, id).Error
+	})
+	return translateError(err, resourceSeries)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/models"
+	"testing"
+)
+
+func TestSeriesPositionTaken(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		author := createAuthor(t, b, "Author")
+		series := &models.Series{Name: "Series"}
+		if err := b.series.Create(series); err != nil {
+			t.Fatalf("create series: %v", err)
+		}
+		inSeries := func(title string, position float64) *models.Book {
+			return &models.Book{Title: title, AuthorID: author.ID, ISBN: title, SeriesID: &series.ID, SeriesPosition: &position}
+		}
+
+		first := inSeries("first", 1)
+		if err := b.books.Create(first); err != nil {
+			t.Fatalf("create book: %v", err)
+		}
+		if err := b.books.Create(inSeries("second", 1)); apperr.From(err).Code != "book_position_taken" {
+			t.Errorf("Create() at a taken position error = %v, want book_position_taken", err)
+		}
+		second := inSeries("second", 2)
+		if err := b.books.Create(second); err != nil {
+			t.Fatalf("create book: %v", err)
+		}
+
+		taken := 1.0
+		second.SeriesPosition = &taken
+		if err := b.books.Update(second); apperr.From(err).Code != "book_position_taken" {
+			t.Errorf("Update() to a taken position error = %v, want book_position_taken", err)
+		}
+
+		// Deleted books give up their position until they are restored
+		if err := b.books.Delete(first.ID); err != nil {
+			t.Fatalf("delete book: %v", err)
+		}
+		third := inSeries("third", 1)
+		if err := b.books.Create(third); err != nil {
+			t.Fatalf("Create() at the position of a deleted book error = %v", err)
+		}
+		if err := b.books.Restore(first.ID); apperr.From(err).Code != "book_position_taken" {
+			t.Errorf("Restore() error = %v, want book_position_taken", err)
+		}
+		if _, err := b.books.GetByID(first.ID); apperr.KindOf(err) != apperr.KindNotFound {
+			t.Errorf("book restored despite the error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"go-rest-api/internal/migrations"
+	"go-rest-api/internal/models"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// backend holds the repositories of one storage backend under test
+type backend struct {
+	authors  AuthorRepository
+	books    BookRepository
+	reviews  ReviewRepository
+	subjects SubjectRepository
+	search   SearchRepository
+	series   SeriesRepository
+	trash    TrashRepository
+}
+
+// forEachBackend runs test against an empty memory store and an empty,
+// migrated SQLite database, which must behave the same
+func forEachBackend(t *testing.T, test func(t *testing.T, b backend)) {
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryStore()
+		test(t, backend{authors: store.Authors(), books: store.Books(), reviews: store.Reviews(), subjects: store.Subjects(), search: store.Search(), series: store.Series(), trash: store.Trash()})
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		db := newSQLiteDB(t)
+		test(t, backend{authors: NewAuthorRepository(db), books: NewBookRepository(db), reviews: NewReviewRepository(db), subjects: NewSubjectRepository(db), search: NewSearchRepository(db), series: NewSeriesRepository(db), trash: NewTrashRepository(db)})
+	})
+}
+
+// newSQLiteDB opens an in-memory SQLite database with every migration applied
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:?_foreign_keys=1"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Every connection to ":memory:" opens its own database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("configure sqlite: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// createAuthor saves an author with the given name
+func createAuthor(t *testing.T, b backend, name string) *models.Author {
+	t.Helper()
+	author := &models.Author{Name: name, BirthDate: "1900-01-01"}
+	if err := b.authors.Create(author); err != nil {
+		t.Fatalf("create author %q: %v", name, err)
+	}
+	return author
+}
+
+// createBook saves a book of an author, without an ISBN-13
+func createBook(t *testing.T, b backend, authorID uint, title string, year int) *models.Book {
+	t.Helper()
+	book := &models.Book{Title: title, AuthorID: authorID, ISBN: title, PublicationYear: year}
+	if err := b.books.Create(book); err != nil {
+		t.Fatalf("create book %q: %v", title, err)
+	}
+	return book
+}
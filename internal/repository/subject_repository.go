@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type subjectRepository struct {
+	db *gorm.DB
+}
+
+// NewSubjectRepository returns a GORM-backed SubjectRepository
+func NewSubjectRepository(db *gorm.DB) SubjectRepository {
+	return &subjectRepository{db: db}
+}
+
+func (r *subjectRepository) Create(subject *models.Subject, parentPath string) error {
+	// The path ends with the subject's own ID, so it is set once that is known
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(subject).Error; err != nil {
+			return err
+		}
+		subject.Path = models.SubjectPath(parentPath, subject.ID)
+		return tx.Model(subject).UpdateColumn("path", subject.Path).Error
+	})
+	return translateError(err, resourceSubject)
+}
+
+func (r *subjectRepository) GetByID(id uint) (*models.Subject, error) {
+	var subject models.Subject
+	result := r.db.First(&subject, id)
+	return &subject, translateError(result.Error, resourceSubject)
+}
+
+func (r *subjectRepository) GetByIDs(ids []uint) ([]models.Subject, error) {
+	var subjects []models.Subject
+	if len(ids) == 0 {
+		return subjects, nil
+	}
+	result := r.db.Where("id IN ?", ids).Order("code").Find(&subjects)
+	return subjects, translateError(result.Error, resourceSubject)
+}
+
+func (r *subjectRepository) GetChildren(parentID *uint, page, pageSize int) ([]models.Subject, int64, error) {
+	var subjects []models.Subject
+	var count int64
+
+	query := func() *gorm.DB {
+		if parentID == nil {
+			return r.db.Model(&models.Subject{}).Where("parent_id IS NULL")
+		}
+		return r.db.Model(&models.Subject{}).Where("parent_id = ?", *parentID)
+	}
+
+	// Get total count
+	if err := query().Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceSubject)
+	}
+
+	// Get paginated subjects
+	offset := (page - 1) * pageSize
+	result := query().Order("code").Offset(offset).Limit(pageSize).Find(&subjects)
+	return subjects, count, translateError(result.Error, resourceSubject)
+}
+
+func (r *subjectRepository) GetAncestors(subject models.Subject) ([]models.Subject, error) {
+	var ancestors []models.Subject
+	ids := subject.AncestorIDs()
+	if len(ids) == 0 {
+		return ancestors, nil
+	}
+	// Deeper subjects have longer paths
+	result := r.db.Where("id IN ?", ids).Order("LENGTH(path)").Find(&ancestors)
+	return ancestors, translateError(result.Error, resourceSubject)
+}
+
+func (r *subjectRepository) GetSubtree(subject models.Subject) ([]models.Subject, error) {
+	var subtree []models.Subject
+	result := r.db.Where("path LIKE ?", subject.Path+"%").Order("path").Find(&subtree)
+	return subtree, translateError(result.Error, resourceSubject)
+}
+
+func (r *subjectRepository) Update(subject *models.Subject) error {
+	return updateVersioned(r.db, subject, &subject.Version, resourceSubject)
+}
+
+func (r *subjectRepository) Move(subject *models.Subject, parent *models.Subject) error {
+	loadedParentID, loadedPath := subject.ParentID, subject.Path
+	ids := []uint{subject.ID}
+	var parentID *uint
+	if parent != nil {
+		ids = append(ids, parent.ID)
+		parentID = &parent.ID
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Moves of their ancestors change the paths read by the caller, so
+		// they are read again under row locks, taken in ID order
+		var current []models.Subject
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&current).Error
+		if err != nil {
+			return err
+		}
+		paths := make(map[uint]string, len(current))
+		for _, row := range current {
+			paths[row.ID] = row.Path
+		}
+		for _, id := range ids {
+			if _, ok := paths[id]; !ok {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		oldPath, parentPath := paths[subject.ID], ""
+		if parent != nil {
+			parentPath = paths[parent.ID]
+			// The subtree of the subject is every path that starts with its own
+			if strings.HasPrefix(parentPath, oldPath) {
+				return errSubjectCycle()
+			}
+		}
+
+		subject.ParentID = parentID
+		subject.Path = models.SubjectPath(parentPath, subject.ID)
+		if err := updateVersioned(tx, subject, &subject.Version, resourceSubject); err != nil {
+			return err
+		}
+
+		// Descendants keep the rest of their path below the moved subject
+		return tx.Model(&models.Subject{}).
+			Where("path LIKE ? AND id <> ?", oldPath+"%", subject.ID).
+			UpdateColumn("path", gorm.Expr("CAST(? AS TEXT) || SUBSTR(path, ?)", subject.Path, len(oldPath)+1)).Error
+	})
+	if err != nil {
+		subject.ParentID, subject.Path = loadedParentID, loadedPath
+	}
+	return translateError(err, resourceSubject)
+}
+
+func (r *subjectRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&models.Subject{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return errInUse(resourceSubject)
+		}
+
+		if err := tx.Where("subject_id = ?", id).Delete(&models.BookSubject{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Subject{}, id).Error
+	})
+	return translateError(err, resourceSubject)
+}
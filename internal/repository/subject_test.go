@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/models"
+	"maps"
+	"testing"
+)
+
+// createSubject saves a subject under parent, or at the top level for nil
+func createSubject(t *testing.T, b backend, code string, parent *models.Subject) *models.Subject {
+	t.Helper()
+	subject := &models.Subject{Code: code, Label: code}
+	parentPath := ""
+	if parent != nil {
+		subject.ParentID = &parent.ID
+		parentPath = parent.Path
+	}
+	if err := b.subjects.Create(subject, parentPath); err != nil {
+		t.Fatalf("create subject %q: %v", code, err)
+	}
+	return subject
+}
+
+// subjectPaths returns the stored paths of subjects keyed by code
+func subjectPaths(t *testing.T, b backend, subjects ...*models.Subject) map[string]string {
+	t.Helper()
+	paths := make(map[string]string, len(subjects))
+	for _, subject := range subjects {
+		stored, err := b.subjects.GetByID(subject.ID)
+		if err != nil {
+			t.Fatalf("get subject %q: %v", subject.Code, err)
+		}
+		paths[stored.Code] = stored.Path
+	}
+	return paths
+}
+
+// subjectTree builds 100 > 110 > 111 and 112, and 200 > 210
+func subjectTree(t *testing.T, b backend) (s100, s110, s111, s112, s200, s210 *models.Subject) {
+	s100 = createSubject(t, b, "100", nil)
+	s110 = createSubject(t, b, "110", s100)
+	s111 = createSubject(t, b, "111", s110)
+	s112 = createSubject(t, b, "112", s110)
+	s200 = createSubject(t, b, "200", nil)
+	s210 = createSubject(t, b, "210", s200)
+	return
+}
+
+func TestSubjectMove(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		s100, s110, s111, s112, s200, s210 := subjectTree(t, b)
+		all := []*models.Subject{s100, s110, s111, s112, s200, s210}
+
+		// Move 110 with its subtree under 210
+		if err := b.subjects.Move(s110, s210); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if s110.ParentID == nil || *s110.ParentID != s210.ID || s110.Path != "/5/6/2/" {
+			t.Errorf("moved subject has parent %v, path %q", s110.ParentID, s110.Path)
+		}
+		want := map[string]string{"100": "/1/", "110": "/5/6/2/", "111": "/5/6/2/3/", "112": "/5/6/2/4/", "200": "/5/", "210": "/5/6/"}
+		if got := subjectPaths(t, b, all...); !maps.Equal(got, want) {
+			t.Errorf("paths = %v, want %v", got, want)
+		}
+
+		subtree, err := b.subjects.GetSubtree(*s200)
+		if err != nil || len(subtree) != 5 {
+			t.Errorf("GetSubtree(200) = %d subjects, %v, want 5", len(subtree), err)
+		}
+		moved, _ := b.subjects.GetByID(s111.ID)
+		ancestors, err := b.subjects.GetAncestors(*moved)
+		if err != nil || len(ancestors) != 3 || ancestors[0].Code != "200" || ancestors[2].Code != "110" {
+			t.Errorf("GetAncestors(111) = %v, %v, want 200, 210, 110", ancestors, err)
+		}
+
+		// And back to the top level
+		if err := b.subjects.Move(s110, nil); err != nil {
+			t.Fatalf("Move() to the top level error = %v", err)
+		}
+		want = map[string]string{"100": "/1/", "110": "/2/", "111": "/2/3/", "112": "/2/4/", "200": "/5/", "210": "/5/6/"}
+		if got := subjectPaths(t, b, all...); !maps.Equal(got, want) {
+			t.Errorf("paths = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSubjectMoveStalePaths(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		s100, s110, s111, _, s200, _ := subjectTree(t, b)
+
+		// s110 and s111 still hold the paths from before 100 moved under 200
+		if err := b.subjects.Move(s100, s200); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if err := b.subjects.Move(s111, s110); err != nil {
+			t.Fatalf("Move() under a stale parent error = %v", err)
+		}
+		if s111.Path != "/5/1/2/3/" {
+			t.Errorf("path = %q, want /5/1/2/3/", s111.Path)
+		}
+	})
+}
+
+func TestSubjectMoveCycle(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		s100, s110, s111, s112, s200, s210 := subjectTree(t, b)
+		all := []*models.Subject{s100, s110, s111, s112, s200, s210}
+		want := subjectPaths(t, b, all...)
+
+		tests := []struct {
+			name    string
+			subject *models.Subject
+			parent  *models.Subject
+		}{
+			{name: "under itself", subject: s110, parent: s110},
+			{name: "under its child", subject: s110, parent: s111},
+			{name: "under a deeper descendant", subject: s100, parent: s112},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				path := tt.subject.Path
+				err := b.subjects.Move(tt.subject, tt.parent)
+				if code := apperr.From(err).Code; code != "subject_cycle" {
+					t.Errorf("Move() error = %v, want subject_cycle", err)
+				}
+				if tt.subject.Path != path {
+					t.Errorf("path = %q after a failed move, want %q", tt.subject.Path, path)
+				}
+			})
+		}
+
+		// The parent's path the caller holds is out of date: 210 moved under
+		// 111 since it was read, so moving 100 under it is a cycle
+		stale := *s210
+		if err := b.subjects.Move(s210, s111); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		if err := b.subjects.Move(s100, &stale); apperr.From(err).Code != "subject_cycle" {
+			t.Errorf("Move() under a descendant with a stale path error = %v, want subject_cycle", err)
+		}
+
+		want["210"] = "/1/2/3/6/"
+		if got := subjectPaths(t, b, all...); !maps.Equal(got, want) {
+			t.Errorf("paths = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository returns a GORM-backed TagRepository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return translateError(r.db.Create(tag).Error, resourceTag)
+}
+
+func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.First(&tag, id)
+	return &tag, translateError(result.Error, resourceTag)
+}
+
+func (r *tagRepository) GetByIDs(ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	result := r.db.Where("id IN ?", ids).Order("LOWER(name), id").Find(&tags)
+	return tags, translateError(result.Error, resourceTag)
+}
+
+func (r *tagRepository) GetAll(page, pageSize int) ([]models.Tag, int64, error) {
+	var tags []models.Tag
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.Tag{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceTag)
+	}
+
+	// Get paginated tags
+	offset := (page - 1) * pageSize
+	result := r.db.Order("LOWER(name), id").Offset(offset).Limit(pageSize).Find(&tags)
+	return tags, count, translateError(result.Error, resourceTag)
+}
+
+func (r *tagRepository) CountBooks(tagIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		TagID     uint
+		BookCount int64
+	}
+	counts := make(map[uint]int64, len(tagIDs))
+	if len(tagIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.Model(&models.BookTag{}).
+		Select("tag_id, COUNT(*) AS book_count").
+		Where("tag_id IN ? AND book_id IN (?)", tagIDs, liveBookIDs(r.db)).
+		Group("tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err, resourceTag)
+	}
+
+	for _, row := range rows {
+		counts[row.TagID] = row.BookCount
+	}
+	return counts, nil
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return updateVersioned(r.db, tag, &tag.Version, resourceTag)
+}
+
+func (r *tagRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Links of deleted books go too, restoring a book does not bring the tag back
+		if err := tx.Where("tag_id = ?", id).Delete(&models.BookTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+	return translateError(err, resourceTag)
+}
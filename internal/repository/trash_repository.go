@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository returns a GORM-backed TrashRepository
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+func (r *trashRepository) Purge(before time.Time) (PurgeCounts, error) {
+	var counts PurgeCounts
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Reviews of an expired book were deleted with it or earlier, so they go
+		// first and are all counted. Authors go last so that the authors of the
+		// purged books can go in the same run.
+		result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Review{})
+		if result.Error != nil {
+			return translateError(result.Error, resourceReview)
+		}
+		counts.Reviews = result.RowsAffected
+
+		// Editions, copies, contributors and classification links refer to the books
+		expired := tx.Unscoped().Model(&models.Book{}).Select("id").Where("deleted_at < ?", before)
+		for _, dependent := range []any{&models.Review{}, &models.Edition{}, &models.Copy{}} {
+			if err := tx.Unscoped().Where("book_id IN (?)", expired).Delete(dependent).Error; err != nil {
+				return translateError(err, resourceBook)
+			}
+		}
+		for _, links := range []any{&models.BookContributor{}, &models.BookGenre{}, &models.BookTag{}, &models.BookSubject{}} {
+			if err := tx.Where("book_id IN (?)", expired).Delete(links).Error; err != nil {
+				return translateError(err, resourceBook)
+			}
+		}
+		result = tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Book{})
+		if result.Error != nil {
+			return translateError(result.Error, resourceBook)
+		}
+		counts.Books = result.RowsAffected
+
+		// Authors of books that are deleted but not yet purged are kept for the next purge
+		result = tx.Unscoped().
+			Where("deleted_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM books WHERE books.author_id = authors.id)").
+			Where("NOT EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.author_id = authors.id)").
+			Delete(&models.Author{})
+		if result.Error != nil {
+			return translateError(result.Error, resourceAuthor)
+		}
+		counts.Authors = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return PurgeCounts{}, err
+	}
+	return counts, nil
+}
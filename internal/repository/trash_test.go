@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/models"
+	"testing"
+	"time"
+)
+
+func TestPurge(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		kept := createAuthor(t, b, "Kept")
+		gone := createAuthor(t, b, "Gone")
+		live := createBook(t, b, kept.ID, "live", 2000)
+		deleted := createBook(t, b, gone.ID, "deleted", 2000)
+		for _, book := range []*models.Book{live, deleted} {
+			if err := b.reviews.Create(&models.Review{BookID: book.ID, Rating: 4}); err != nil {
+				t.Fatalf("create review: %v", err)
+			}
+		}
+		liveReview := &models.Review{BookID: live.ID, Rating: 2}
+		if err := b.reviews.Create(liveReview); err != nil {
+			t.Fatalf("create review: %v", err)
+		}
+		if err := b.reviews.Delete(liveReview.ID); err != nil {
+			t.Fatalf("delete review: %v", err)
+		}
+		// The book of gone is purged in the same run, so gone can go too
+		if err := b.authors.Delete(gone.ID, AuthorDeletion{Cascade: true}); err != nil {
+			t.Fatalf("delete author: %v", err)
+		}
+
+		counts, err := b.trash.Purge(time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("Purge() error = %v", err)
+		}
+		if want := (PurgeCounts{Books: 1, Reviews: 2, Authors: 1}); counts != want {
+			t.Errorf("Purge() = %+v, want %+v", counts, want)
+		}
+		if _, err := b.books.GetDeletedByID(deleted.ID); apperr.KindOf(err) != apperr.KindNotFound {
+			t.Errorf("purged book still found: %v", err)
+		}
+		if _, err := b.authors.GetDeletedByID(gone.ID); apperr.KindOf(err) != apperr.KindNotFound {
+			t.Errorf("purged author still found: %v", err)
+		}
+		if reviews, err := b.reviews.GetByBookID(live.ID, true); err != nil || len(reviews) != 1 {
+			t.Errorf("reviews of the live book = %d, %v, want the one that was not deleted", len(reviews), err)
+		}
+
+		if counts, err := b.trash.Purge(time.Now().Add(time.Hour)); err != nil || counts != (PurgeCounts{}) {
+			t.Errorf("second Purge() = %+v, %v, want nothing purged", counts, err)
+		}
+	})
+}
+
+func TestPurgeKeepsRecentAndReferencedAuthors(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		author := createAuthor(t, b, "Author")
+		book := createBook(t, b, author.ID, "book", 2000)
+		if err := b.authors.Delete(author.ID, AuthorDeletion{Cascade: true}); err != nil {
+			t.Fatalf("delete author: %v", err)
+		}
+
+		counts, err := b.trash.Purge(time.Now().Add(-time.Hour))
+		if err != nil || counts != (PurgeCounts{}) {
+			t.Errorf("Purge() of records deleted an hour ago = %+v, %v, want nothing purged", counts, err)
+		}
+
+		// A book deleted later than the author keeps the author until it is purged
+		if err := b.books.Restore(book.ID); err != nil {
+			t.Fatalf("restore book: %v", err)
+		}
+		cutoff := time.Now().Add(time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+		if err := b.books.Delete(book.ID); err != nil {
+			t.Fatalf("delete book: %v", err)
+		}
+		counts, err = b.trash.Purge(cutoff)
+		if err != nil || counts != (PurgeCounts{}) {
+			t.Errorf("Purge() = %+v, %v, want the author kept for its book", counts, err)
+		}
+		if _, err := b.authors.GetDeletedByID(author.ID); err != nil {
+			t.Errorf("author purged while a deleted book refers to it: %v", err)
+		}
+	})
+}
+
+func TestPurgeRollsBack(t *testing.T) {
+	db := newSQLiteDB(t)
+	b := backend{authors: NewAuthorRepository(db), books: NewBookRepository(db), reviews: NewReviewRepository(db)}
+	author := createAuthor(t, b, "Author")
+	book := createBook(t, b, author.ID, "book", 2000)
+	review := &models.Review{BookID: book.ID, Rating: 3}
+	if err := b.reviews.Create(review); err != nil {
+		t.Fatalf("create review: %v", err)
+	}
+	if err := b.reviews.Delete(review.ID); err != nil {
+		t.Fatalf("delete review: %v", err)
+	}
+	if err := b.books.Delete(book.ID); err != nil {
+		t.Fatalf("delete book: %v", err)
+	}
+
+	// Purging books fails once the reviews are gone
+	if err := db.Migrator().DropTable("book_contributors"); err != nil {
+		t.Fatalf("drop table: %v", err)
+	}
+	if _, err := NewTrashRepository(db).Purge(time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("Purge() error = nil, want the failure to purge books")
+	}
+	if _, err := b.reviews.GetDeletedByID(review.ID); err != nil {
+		t.Errorf("review purged although the purge failed: %v", err)
+	}
+	if _, err := b.books.GetDeletedByID(book.ID); err != nil {
+		t.Errorf("book purged although the purge failed: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"go-rest-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository returns a GORM-backed UserRepository
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return translateError(r.db.Create(user).Error, resourceUser)
+}
+
+func (r *userRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	result := r.db.First(&user, id)
+	return &user, translateError(result.Error, resourceUser)
+}
+
+func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	result := r.db.Where("email = ?", email).First(&user)
+	return &user, translateError(result.Error, resourceUser)
+}
+
+func (r *userRepository) GetAll(page, pageSize int) ([]models.User, int64, error) {
+	var users []models.User
+	var count int64
+
+	// Get total count
+	if err := r.db.Model(&models.User{}).Count(&count).Error; err != nil {
+		return nil, 0, translateError(err, resourceUser)
+	}
+
+	// Get paginated users
+	offset := (page - 1) * pageSize
+	result := r.db.Order("id ASC").Offset(offset).Limit(pageSize).Find(&users)
+	return users, count, translateError(result.Error, resourceUser)
+}
+
+func (r *userRepository) Update(user *models.User) error {
+	return translateError(r.db.Save(user).Error, resourceUser)
+}
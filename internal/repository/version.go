@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateVersioned saves every column of model, which was loaded at *version,
+// and bumps the version. The update only applies while the row is still at the
+// loaded version, so a concurrent update fails with a _modified conflict
+// instead of being silently overwritten.
+func updateVersioned(db *gorm.DB, model any, version *uint, resource string) error {
+	loaded := *version
+	*version = loaded + 1
+
+	result := db.Model(model).Where("version = ?", loaded).Select("*").Omit(clause.Associations).Updates(model)
+	if result.Error != nil {
+		*version = loaded
+		return translateError(result.Error, resource)
+	}
+	if result.RowsAffected == 0 {
+		*version = loaded
+		return errModified(resource)
+	}
+	return nil
+}
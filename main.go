@@ -6,8 +6,13 @@ import (
 
 	// Import the docs package for Swagger
 	_ "go-rest-api/docs"
+	"go-rest-api/internal/apperr"
+	"go-rest-api/internal/auth"
 	"go-rest-api/internal/database"
 	"go-rest-api/internal/handlers"
+	"go-rest-api/internal/middleware"
+	"go-rest-api/internal/models"
+	"go-rest-api/internal/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -30,14 +35,104 @@ import (
 // @host localhost:8080
 // @BasePath /
 // @schemes http
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /api/v1/auth/login, sent as "Bearer <token>"
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created by an admin under /api/v1/admin/api-keys
 func main() {
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		log.Printf("Warning: .env file not found or error loading: %v", err)
 	}
 
-	// Connect to database
-	database.ConnectDatabase()
+	// Schema migration subcommands: migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(database.ConnectDatabase(), os.Args[2:])
+		return
+	}
+
+	// Build repositories for the configured storage driver
+	var bookRepo repository.BookRepository
+	var authorRepo repository.AuthorRepository
+	var reviewRepo repository.ReviewRepository
+	var genreRepo repository.GenreRepository
+	var tagRepo repository.TagRepository
+	var subjectRepo repository.SubjectRepository
+	var seriesRepo repository.SeriesRepository
+	var editionRepo repository.EditionRepository
+	var copyRepo repository.CopyRepository
+	var publisherRepo repository.PublisherRepository
+	var searchRepo repository.SearchRepository
+	var trashRepo repository.TrashRepository
+	var userRepo repository.UserRepository
+	var apiKeyRepo repository.APIKeyRepository
+
+	switch os.Getenv("STORAGE_DRIVER") {
+	case "memory":
+		log.Println("Using in-memory storage, data will be lost on restart")
+		store := repository.NewMemoryStore()
+		bookRepo = store.Books()
+		authorRepo = store.Authors()
+		reviewRepo = store.Reviews()
+		genreRepo = store.Genres()
+		tagRepo = store.Tags()
+		subjectRepo = store.Subjects()
+		seriesRepo = store.Series()
+		editionRepo = store.Editions()
+		copyRepo = store.Copies()
+		publisherRepo = store.Publishers()
+		searchRepo = store.Search()
+		trashRepo = store.Trash()
+		userRepo = store.Users()
+		apiKeyRepo = store.APIKeys()
+	case "", "gorm":
+		// Connect to database
+		db := database.ConnectDatabase()
+		checkMigrations(db)
+		bookRepo = repository.NewBookRepository(db)
+		authorRepo = repository.NewAuthorRepository(db)
+		reviewRepo = repository.NewReviewRepository(db)
+		genreRepo = repository.NewGenreRepository(db)
+		tagRepo = repository.NewTagRepository(db)
+		subjectRepo = repository.NewSubjectRepository(db)
+		seriesRepo = repository.NewSeriesRepository(db)
+		editionRepo = repository.NewEditionRepository(db)
+		copyRepo = repository.NewCopyRepository(db)
+		publisherRepo = repository.NewPublisherRepository(db)
+		searchRepo = repository.NewSearchRepository(db)
+		trashRepo = repository.NewTrashRepository(db)
+		userRepo = repository.NewUserRepository(db)
+		apiKeyRepo = repository.NewAPIKeyRepository(db)
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q, expected \"gorm\" or \"memory\"", os.Getenv("STORAGE_DRIVER"))
+	}
+
+	// Build handlers
+	bookHandler := handlers.NewBookHandler(bookRepo, authorRepo, genreRepo, tagRepo, subjectRepo, seriesRepo)
+	authorHandler := handlers.NewAuthorHandler(authorRepo)
+	reviewHandler := handlers.NewReviewHandler(reviewRepo, bookRepo)
+	genreHandler := handlers.NewGenreHandler(genreRepo)
+	tagHandler := handlers.NewTagHandler(tagRepo)
+	subjectHandler := handlers.NewSubjectHandler(subjectRepo)
+	seriesHandler := handlers.NewSeriesHandler(seriesRepo)
+	editionHandler := handlers.NewEditionHandler(editionRepo, bookRepo, publisherRepo)
+	copyHandler := handlers.NewCopyHandler(copyRepo, bookRepo)
+	publisherHandler := handlers.NewPublisherHandler(publisherRepo)
+	searchHandler := handlers.NewSearchHandler(searchRepo)
+	trashHandler := handlers.NewTrashHandler(trashRepo, softDeleteRetention())
+
+	// Authentication
+	tokens := auth.NewTokenServiceFromEnv()
+	authHandler := handlers.NewAuthHandler(userRepo, tokens)
+	userHandler := handlers.NewUserHandler(userRepo)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	ensureAdmin(userRepo)
 
 	// Set Gin to release mode in production
 	if os.Getenv("GIN_MODE") == "release" {
@@ -47,11 +142,21 @@ func main() {
 	// Initialize router
 	r := gin.Default()
 
+	// Every request gets an ID, and errors recorded by handlers and
+	// middleware are rendered as application/problem+json
+	r.Use(middleware.RequestID(), middleware.ErrorHandler())
+
+	// Unknown routes use the same error format
+	r.NoRoute(func(c *gin.Context) {
+		_ = c.Error(apperr.NotFound("route_not_found", "Route not found"))
+	})
+
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, If-Match, If-None-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -71,35 +176,167 @@ func main() {
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
+		// Account and token routes
+		authRoutes := v1.Group("/auth")
+		{
+			authRoutes.POST("/register", authHandler.Register)
+			authRoutes.POST("/login", authHandler.Login)
+			authRoutes.POST("/refresh", authHandler.Refresh)
+		}
+
+		// Everything else requires an access token or API key for writes
+		api := v1.Group("", middleware.Authenticate(tokens, apiKeyRepo))
+
+		// Routes that only read but need a request body use POST, and like
+		// other reads answer anonymous callers
+		reads := v1.Group("", middleware.AuthenticateReads(tokens, apiKeyRepo))
+
+		// API keys need the scope of each route, catalog writes are
+		// reserved for librarians and admins
+		booksReader := middleware.RequireScope(auth.ScopeBooksRead)
+		booksWriter := middleware.Authorize(auth.ScopeBooksWrite, models.RoleLibrarian, models.RoleAdmin)
+		authorsReader := middleware.RequireScope(auth.ScopeAuthorsRead)
+		authorsWriter := middleware.Authorize(auth.ScopeAuthorsWrite, models.RoleLibrarian, models.RoleAdmin)
+		reviewsReader := middleware.RequireScope(auth.ScopeReviewsRead)
+		reviewsWriter := middleware.RequireScope(auth.ScopeReviewsWrite)
+
+		// Updates and deletes honor If-Match, and with REQUIRE_IF_MATCH=true must send it
+		ifMatch := middleware.RequireIfMatch(os.Getenv("REQUIRE_IF_MATCH") == "true")
+
+		// Search across books and authors
+		api.GET("/search", booksReader, authorsReader, searchHandler.Search)
+
 		// Book routes
-		books := v1.Group("/books")
+		reads.POST("/books/isbn/lookup", booksReader, bookHandler.LookupISBNs)
+		books := api.Group("/books")
 		{
-			books.GET("", handlers.GetBooks)
-			books.GET("/:id", handlers.GetBook)
-			books.POST("", handlers.CreateBook)
-			books.PUT("/:id", handlers.UpdateBook)
-			books.DELETE("/:id", handlers.DeleteBook)
+			books.GET("", booksReader, bookHandler.GetBooks)
+			books.GET("/:id", booksReader, bookHandler.GetBook)
+			books.GET("/isbn/:isbn", booksReader, bookHandler.GetBookByISBN)
+			books.POST("", booksWriter, bookHandler.CreateBook)
+			books.PUT("/:id", booksWriter, ifMatch, bookHandler.UpdateBook)
+			books.PATCH("/:id", booksWriter, ifMatch, bookHandler.PatchBook)
+			books.DELETE("/:id", booksWriter, ifMatch, bookHandler.DeleteBook)
+			books.POST("/:id/restore", booksWriter, bookHandler.RestoreBook)
 
 			// Review routes related to books
-			books.GET("/:id/reviews", handlers.GetBookReviews)
-			books.POST("/:id/reviews", handlers.AddReview)
+			books.GET("/:id/reviews", reviewsReader, reviewHandler.GetBookReviews)
+			books.POST("/:id/reviews", reviewsWriter, reviewHandler.AddReview)
+
+			// Edition routes related to books
+			books.GET("/:id/editions", booksReader, editionHandler.GetBookEditions)
+			books.POST("/:id/editions", booksWriter, editionHandler.AddEdition)
+
+			// Copy routes, nested under their book
+			books.GET("/:id/copies", booksReader, copyHandler.GetBookCopies)
+			books.POST("/:id/copies", booksWriter, copyHandler.AddCopy)
+			books.GET("/:id/copies/:copy_id", booksReader, copyHandler.GetCopy)
+			books.PUT("/:id/copies/:copy_id", booksWriter, ifMatch, copyHandler.UpdateCopy)
+			books.PATCH("/:id/copies/:copy_id", booksWriter, ifMatch, copyHandler.PatchCopy)
+			books.DELETE("/:id/copies/:copy_id", booksWriter, ifMatch, copyHandler.DeleteCopy)
 		}
 
 		// Author routes
-		authors := v1.Group("/authors")
+		authors := api.Group("/authors")
+		{
+			authors.GET("", authorsReader, authorHandler.GetAuthors)
+			authors.GET("/:id", authorsReader, authorHandler.GetAuthor)
+			authors.POST("", authorsWriter, authorHandler.CreateAuthor)
+			authors.PUT("/:id", authorsWriter, ifMatch, authorHandler.UpdateAuthor)
+			authors.PATCH("/:id", authorsWriter, ifMatch, authorHandler.PatchAuthor)
+			authors.DELETE("/:id", authorsWriter, ifMatch, authorHandler.DeleteAuthor)
+			authors.POST("/:id/restore", authorsWriter, authorHandler.RestoreAuthor)
+		}
+
+		// Genre, tag and subject routes, which classify books and share their scopes
+		genres := api.Group("/genres")
+		{
+			genres.GET("", booksReader, genreHandler.GetGenres)
+			genres.GET("/:id", booksReader, genreHandler.GetGenre)
+			genres.POST("", booksWriter, genreHandler.CreateGenre)
+			genres.PUT("/:id", booksWriter, ifMatch, genreHandler.UpdateGenre)
+			genres.PATCH("/:id", booksWriter, ifMatch, genreHandler.PatchGenre)
+			genres.DELETE("/:id", booksWriter, ifMatch, genreHandler.DeleteGenre)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.GET("", booksReader, tagHandler.GetTags)
+			tags.GET("/:id", booksReader, tagHandler.GetTag)
+			tags.POST("", booksWriter, tagHandler.CreateTag)
+			tags.PUT("/:id", booksWriter, ifMatch, tagHandler.UpdateTag)
+			tags.PATCH("/:id", booksWriter, ifMatch, tagHandler.PatchTag)
+			tags.DELETE("/:id", booksWriter, ifMatch, tagHandler.DeleteTag)
+		}
+
+		subjects := api.Group("/subjects")
+		{
+			subjects.GET("", booksReader, subjectHandler.GetSubjects)
+			subjects.GET("/:id", booksReader, subjectHandler.GetSubject)
+			subjects.GET("/:id/tree", booksReader, subjectHandler.GetSubjectTree)
+			subjects.POST("", booksWriter, subjectHandler.CreateSubject)
+			subjects.PUT("/:id", booksWriter, ifMatch, subjectHandler.UpdateSubject)
+			subjects.PATCH("/:id", booksWriter, ifMatch, subjectHandler.PatchSubject)
+			subjects.POST("/:id/move", booksWriter, ifMatch, subjectHandler.MoveSubject)
+			subjects.DELETE("/:id", booksWriter, ifMatch, subjectHandler.DeleteSubject)
+		}
+
+		// Series routes, which order books and share their scopes
+		series := api.Group("/series")
+		{
+			series.GET("", booksReader, seriesHandler.GetAllSeries)
+			series.GET("/:id", booksReader, seriesHandler.GetSeries)
+			series.POST("", booksWriter, seriesHandler.CreateSeries)
+			series.PUT("/:id", booksWriter, ifMatch, seriesHandler.UpdateSeries)
+			series.PATCH("/:id", booksWriter, ifMatch, seriesHandler.PatchSeries)
+			series.DELETE("/:id", booksWriter, ifMatch, seriesHandler.DeleteSeries)
+		}
+
+		// Edition routes (for single editions, update and delete) and
+		// publisher routes, which share the book scopes
+		editions := api.Group("/editions")
+		{
+			editions.GET("/:id", booksReader, editionHandler.GetEdition)
+			editions.PUT("/:id", booksWriter, ifMatch, editionHandler.UpdateEdition)
+			editions.PATCH("/:id", booksWriter, ifMatch, editionHandler.PatchEdition)
+			editions.DELETE("/:id", booksWriter, ifMatch, editionHandler.DeleteEdition)
+		}
+
+		publishers := api.Group("/publishers")
+		{
+			publishers.GET("", booksReader, publisherHandler.GetPublishers)
+			publishers.GET("/:id", booksReader, publisherHandler.GetPublisher)
+			publishers.POST("", booksWriter, publisherHandler.CreatePublisher)
+			publishers.PUT("/:id", booksWriter, ifMatch, publisherHandler.UpdatePublisher)
+			publishers.PATCH("/:id", booksWriter, ifMatch, publisherHandler.PatchPublisher)
+			publishers.DELETE("/:id", booksWriter, ifMatch, publisherHandler.DeletePublisher)
+		}
+
+		// Review routes (for single reviews, update and delete)
+		reviews := api.Group("/reviews")
+		{
+			reviews.GET("/:id", reviewsReader, reviewHandler.GetReview)
+			reviews.PUT("/:id", reviewsWriter, ifMatch, reviewHandler.UpdateReview)
+			reviews.PATCH("/:id", reviewsWriter, ifMatch, reviewHandler.PatchReview)
+			reviews.DELETE("/:id", reviewsWriter, ifMatch, reviewHandler.DeleteReview)
+			reviews.POST("/:id/restore", reviewsWriter, reviewHandler.RestoreReview)
+		}
+
+		// User management routes
+		users := api.Group("/users", middleware.RequireRole(models.RoleAdmin))
 		{
-			authors.GET("", handlers.GetAuthors)
-			authors.GET("/:id", handlers.GetAuthor)
-			authors.POST("", handlers.CreateAuthor)
-			authors.PUT("/:id", handlers.UpdateAuthor)
-			authors.DELETE("/:id", handlers.DeleteAuthor)
+			users.GET("", userHandler.GetUsers)
+			users.PUT("/:id/role", userHandler.UpdateUserRole)
 		}
 
-		// Review routes (for update and delete)
-		reviews := v1.Group("/reviews")
+		// Admin routes, users only
+		admin := api.Group("/admin", middleware.RequireRole(models.RoleAdmin))
 		{
-			reviews.PUT("/:id", handlers.UpdateReview)
-			reviews.DELETE("/:id", handlers.DeleteReview)
+			admin.GET("/api-keys", apiKeyHandler.GetAPIKeys)
+			admin.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			admin.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+			admin.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+			admin.POST("/purge", trashHandler.Purge)
 		}
 	}
 
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"go-rest-api/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: main migrate up|down|status"
+
+// runMigrate handles the `migrate up|down|status` subcommands
+func runMigrate(db *gorm.DB, args []string) {
+	if len(args) != 1 {
+		log.Fatal(migrateUsage)
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up()
+		for _, migration := range ran {
+			log.Printf("Applied %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		if len(ran) == 0 {
+			log.Println("Database is up to date")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		if migration == nil {
+			log.Println("No migrations to roll back")
+			return
+		}
+		log.Printf("Rolled back %04d_%s", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(os.Stdout, "%04d_%-30s %s\n", status.Version, status.Name, applied)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+// checkMigrations refuses to serve a database with pending migrations unless
+// MIGRATE_ON_START=true, in which case they are applied first
+func checkMigrations(db *gorm.DB) {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if os.Getenv("MIGRATE_ON_START") == "true" {
+		ran, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Printf("Applied %d pending migration(s)", len(ran))
+		return
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		log.Fatalf("Failed to read migration status: %v", err)
+	}
+	if pending > 0 {
+		log.Fatalf("%d pending migration(s), run `main migrate up` or set MIGRATE_ON_START=true", pending)
+	}
+}